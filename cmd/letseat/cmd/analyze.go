@@ -8,10 +8,12 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	letseat "github.com/drewstinnett/letseat/pkg"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 // analyzeCmd represents the analyze command
@@ -23,40 +25,111 @@ func newAnalyzeCmd() *cobra.Command {
 		RunE:    runAnalyze,
 	}
 	bindFilter(cmd)
+	bindGrouping(cmd)
+	bindNormalize(cmd)
+	cmd.Flags().String("rank-by", string(letseat.RankByBayesian), "How to rank the highest rated places (average, bayesian or confidence)")
 	return cmd
 }
 
+func bindGrouping(cmd *cobra.Command) {
+	cmd.Flags().String("by", string(letseat.GroupByLocation), "Group places by brand or location")
+}
+
+func newGroupingWithCmd(cmd *cobra.Command) (letseat.Grouping, error) {
+	return letseat.ParseGrouping(mustGetCmd[string](*cmd, "by"))
+}
+
+func bindNormalize(cmd *cobra.Command) {
+	cmd.Flags().String("normalize", "", "Adjust ratings for harsh or generous raters (none, mean or zscore). Defaults to 'normalize' in the config file")
+}
+
+// newNormalizationWithCmd returns the normalization from the flag, falling
+// back to the config file
+func newNormalizationWithCmd(cmd *cobra.Command) (letseat.Normalization, error) {
+	if cmd.Flags().Changed("normalize") {
+		return letseat.ParseNormalization(mustGetCmd[string](*cmd, "normalize"))
+	}
+	return letseat.ParseNormalization(viper.GetString("normalize"))
+}
+
 func bindFilter(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("mode", []string{}, "Only include meals with these service modes (dine-in, takeout, delivery, drive-thru, food-truck)")
+	cmd.Flags().StringSlice("meal", []string{}, "Only include these meal types (breakfast, lunch, dinner, late-night, snack)")
 	cmd.Flags().Bool("only-takeout", false, "Only include takeout meals")
 	cmd.Flags().Bool("only-dinein", false, "Only include dine-in meals")
-	cmd.Flags().StringP("earliest", "e", "90d", "Earliest date to include")
+	panicIfErr(cmd.Flags().MarkDeprecated("only-takeout", "use --mode takeout instead"))
+	panicIfErr(cmd.Flags().MarkDeprecated("only-dinein", "use --mode dine-in instead"))
+	cmd.Flags().StringP("earliest", "e", "90d", "Earliest date to include, like 90d, 6mo, 2023-06-01, \"since 2023-01-01\" or \"last month\"")
+	cmd.Flags().StringP("latest", "l", "", "Latest date to include, like 2023-12-31, 1y or \"last year\"")
+	cmd.Flags().String("max-price", "", "Only include places at or below this price level, like $$ or 2")
+	cmd.Flags().StringSlice("place", []string{}, "Only include these places")
+	cmd.Flags().StringSlice("exclude-place", []string{}, "Leave out these places")
+	cmd.Flags().StringSlice("with", []string{}, "Only include meals where all of these people were there")
+	cmd.Flags().StringSlice("weekday", []string{}, "Only include meals on these days, like fri,sat or weekends")
+	cmd.Flags().Float64("min-rating", 0, "Only include meals with at least this average rating")
+	cmd.Flags().Float64("max-rating", 0, "Only include meals with at most this average rating")
+	cmd.Flags().String("min-cost", "", "Only include meals that cost at least this much")
+	cmd.Flags().String("max-cost", "", "Only include meals that cost at most this much")
+	bindWhere(cmd)
+	cmd.Flags().String("preset", "", "Use a named set of filters from the config file. Flags given on the command line win")
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		return applyPreset(cmd)
+	}
+}
+
+func bindWhere(cmd *cobra.Command) {
+	cmd.Flags().String("where", "", `Only include meals matching an expression, like 'takeout and cost < 40 and weekday in (fri, sat)'`)
 }
 
 func runAnalyze(cmd *cobra.Command, args []string) error {
+	f, err := newEntryFilterWithCmd(cmd)
+	if err != nil {
+		return err
+	}
+	n, err := newNormalizationWithCmd(cmd)
+	if err != nil {
+		return err
+	}
 	diary := letseat.New(
-		letseat.WithFilter(*mustNewEntryFilterWithCmd(cmd)),
+		letseat.WithFilter(*f),
+		letseat.WithNormalization(n),
 		letseat.WithDBFilename(mustGetCmd[string](*cmd, "data")),
 	)
 
+	by, err := newGroupingWithCmd(cmd)
+	if err != nil {
+		return err
+	}
+	rankBy, err := letseat.ParseRankBy(mustGetCmd[string](*cmd, "rank-by"))
+	if err != nil {
+		return err
+	}
+
 	// Find best rated mealsxx
-	placesDetails := diary.PlaceDetails()
+	placesDetails := diary.PlaceDetailsBy(by)
 	if len(placesDetails) == 0 {
 		return fmt.Errorf("no entries found! Try adding some with %v log", os.Args[0])
 	}
 
 	// Print highest rated
-	sort.Sort(placesDetails)
+	placesDetails.SortBy(rankBy)
 
 	ratings := []string{listHeader("\nHighest Rated")}
 	for _, i := range placesDetails {
 		ratings = append(ratings, ratingRow.Render(
-			lipgloss.JoinHorizontal(lipgloss.Top, ratingKey.Render(i.Name), ratingItem.Render(letseat.Stars(i.AverageRating, "★"))),
+			lipgloss.JoinHorizontal(lipgloss.Top,
+				ratingKey.Render(placeLabel(i)),
+				ratingItem.Render(letseat.Stars(i.ScoreFor(rankBy), "★")),
+				ratingNote.Render(ratingDetail(i, rankBy)),
+			),
 		))
 	}
 	// Set up styling
 	doc := strings.Builder{}
+	entries := diary.Entries()
 	doc.WriteString(lipgloss.JoinVertical(lipgloss.Left,
-		titleStyle.Render(fmt.Sprintf("Most Popular: %v\n", diary.MostPopularPlace())),
+		titleStyle.Render(fmt.Sprintf("Most Popular: %v", diary.MostPopularPlace())),
+		titleStyle.Render(fmt.Sprintf("Total Spent: %v\n", entries.TotalCost())),
 		lipgloss.JoinVertical(lipgloss.Left, ratings...),
 	))
 
@@ -67,9 +140,20 @@ func runAnalyze(cmd *cobra.Command, args []string) error {
 
 	lvisited := vistedStrings(placesDetails, *cmd)
 	doc.WriteString(lipgloss.JoinVertical(lipgloss.Left, lvisited...))
+	doc.WriteString(lipgloss.JoinVertical(lipgloss.Left, mealStrings(entries.MealBreakdown())...))
+	if prices := priceStrings(placesDetails); len(prices) > 1 {
+		doc.WriteString(lipgloss.JoinVertical(lipgloss.Left, prices...))
+	}
+	if trending := trendStrings(placesDetails); len(trending) > 1 {
+		doc.WriteString(lipgloss.JoinVertical(lipgloss.Left, trending...))
+	}
+	now := getCurrentDate(cmd)
+	if overdue := overdueStrings(diary.OverdueBy(by, now), now); len(overdue) > 1 {
+		doc.WriteString(lipgloss.JoinVertical(lipgloss.Left, overdue...))
+	}
+	doc.WriteString(lipgloss.JoinVertical(lipgloss.Left, exploreStrings(diary, now)...))
 	doc.WriteString("\n\n")
 
-	entries := diary.Entries()
 	lists := topList(entries.PeopleEnhanced())
 	doc.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, lists...))
 
@@ -84,7 +168,7 @@ func topList(people []letseat.Person) []string {
 
 		topx := topn[0:min(len(topn), 3)]
 		topxI := make([]string, len(topx)+1)
-		topxI[0] = listHeader(person.Name)
+		topxI[0] = listHeader(fmt.Sprintf("%v (%v visits)", person.Name, person.Visits))
 		for idx, topxitem := range topx {
 			topxI[idx+1] = listItem(topxitem)
 		}
@@ -100,15 +184,127 @@ func topList(people []letseat.Person) []string {
 func vistedStrings(pd letseat.PlaceDetails, cmd cobra.Command) []string {
 	highlightTop := 3
 	lvisited := []string{listHeader("\n\nLast Visited")}
-	for i, v := range pd {
+	highlighted := 0
+	for _, v := range pd {
 		lastD := int(getCurrentDate(&cmd).Sub(*v.LastVisit).Hours() / 24)
 		var li string
-		if i < highlightTop {
-			li = listItemMajor(fmt.Sprintf("%20v %10v days ago", v.Name, lastD))
+		// Closed places keep their history, but aren't worth highlighting
+		if highlighted < highlightTop && !v.IsClosed() {
+			li = listItemMajor(fmt.Sprintf("%20v %10v days ago", placeLabel(v), lastD))
+			highlighted++
 		} else {
-			li = listItem(fmt.Sprintf("%20v %10v days ago", v.Name, lastD))
+			li = listItem(fmt.Sprintf("%20v %10v days ago", placeLabel(v), lastD))
 		}
 		lvisited = append(lvisited, li)
 	}
 	return lvisited
 }
+
+// priceStrings lists the places where what was spent doesn't line up with the
+// declared price level
+func priceStrings(pd letseat.PlaceDetails) []string {
+	ret := []string{listHeader("\n\nPrice Check")}
+	for _, p := range pd {
+		if declared := p.DeclaredPrice(); declared != 0 && p.ActualPrice != 0 && declared != p.ActualPrice {
+			ret = append(ret, listItem(fmt.Sprintf("%20v %v", placeLabel(p), p.PriceComparison())))
+		}
+	}
+	return ret
+}
+
+// rankLabels name the score each ranking uses, when it isn't the plain average
+var rankLabels = map[letseat.RankBy]string{
+	letseat.RankByBayesian:   "bayesian",
+	letseat.RankByConfidence: "at worst",
+}
+
+// ratingDetail shows the score a place was ranked on, and how much to trust
+// it, like "4.1 bayesian, 4.3 average over 12 visits (3.9-4.6)"
+func ratingDetail(p letseat.PlaceDetail, by letseat.RankBy) string {
+	ret := fmt.Sprintf("%.1f average over %v", p.AverageRating, plural(p.Rated(), "visit"))
+	if label, ok := rankLabels[by]; ok {
+		ret = fmt.Sprintf("%.1f %v, %v", p.ScoreFor(by), label, ret)
+	}
+	if p.Rated() > 1 {
+		ret += fmt.Sprintf(" (%v)", p.Confidence)
+	}
+	return ret
+}
+
+// trendStrings lists the places whose ratings are clearly going up or down
+func trendStrings(pd letseat.PlaceDetails) []string {
+	ret := []string{listHeader("\n\nTrending")}
+	for _, p := range pd.Trending() {
+		arrow := "↑"
+		if p.Trend.Direction() == letseat.TrendDeclining {
+			arrow = "↓"
+		}
+		ret = append(ret, listItem(fmt.Sprintf("%20v %v %v", placeLabel(p), arrow, p.Trend)))
+	}
+	return ret
+}
+
+// overdueStrings lists the favorites that have gone much longer than usual
+// without a visit
+func overdueStrings(overdue letseat.PlaceDetails, now time.Time) []string {
+	ret := []string{listHeader("\n\nOverdue Favorites")}
+	for _, p := range overdue {
+		ret = append(ret, listItem(fmt.Sprintf("%20v %v", placeLabel(p), overdueDetail(p, now))))
+	}
+	return ret
+}
+
+// overdueDetail is how late a visit is, like "45 days ago, usually every 14 days"
+func overdueDetail(p letseat.PlaceDetail, now time.Time) string {
+	return fmt.Sprintf("%v days ago, usually every %v days", p.DaysSince(now), p.CadenceDays())
+}
+
+// exploreStrings sums up how many new places were tried, and how the yearly
+// goal is going if there is one
+func exploreStrings(diary *letseat.Diary, now time.Time) []string {
+	total := letseat.ExplorationPeriod{}
+	for _, p := range diary.Exploration("2006") {
+		total.New += p.New
+		total.Repeat += p.Repeat
+	}
+	if visits := total.New + total.Repeat; visits > 0 {
+		total.Ratio = float64(total.New) / float64(visits)
+	}
+	ret := []string{listHeader("\n\nExploration"), listItem(total.String())}
+	if goal := getNewPlacesGoal(); goal > 0 {
+		progress := diary.NewPlacesGoal(goal, now)
+		item := listItem
+		if !progress.OnTrack() {
+			item = listItemMajor
+		}
+		ret = append(ret, item(progress.String()))
+	}
+	return ret
+}
+
+// placeLabel is the name of a place, marked if it's closed. Markers are kept
+// short so they fit in the columns
+func placeLabel(p letseat.PlaceDetail) string {
+	if p.Place == nil {
+		return p.Name
+	}
+	switch p.Place.Status {
+	case letseat.StatusClosed:
+		return p.Name + " ✗"
+	case letseat.StatusTemporarilyClosed:
+		return p.Name + " ⏸"
+	}
+	return p.Name
+}
+
+func mealStrings(meals []letseat.MealSummary) []string {
+	ret := []string{listHeader("\n\nMeals")}
+	for _, m := range meals {
+		ret = append(ret, ratingRow.Render(lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			ratingKey.Render(fmt.Sprintf("%v (%v)", m.Meal, m.Visits)),
+			ratingItem.Render(letseat.Stars(m.AverageRating, "★")),
+		)))
+	}
+	return ret
+}
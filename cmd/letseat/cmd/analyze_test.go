@@ -1,5 +1,12 @@
 package cmd
 
+import (
+	"testing"
+
+	letseat "github.com/drewstinnett/letseat/pkg"
+	"github.com/stretchr/testify/require"
+)
+
 /*
 func TestAnalyze(t *testing.T) {
 	b := bytes.NewBufferString("")
@@ -15,3 +22,9 @@ func TestAnalyze(t *testing.T) {
 	assert.Contains(t, b.String(), "Old Person Wings         62 days ago")
 }
 */
+
+func TestRatingDetail(t *testing.T) {
+	p := letseat.PlaceDetail{AverageRating: 3, BayesianRating: 3.4}
+	require.Equal(t, "3.0 average over 0 visits", ratingDetail(p, letseat.RankByAverage))
+	require.Equal(t, "3.4 bayesian, 3.0 average over 0 visits", ratingDetail(p, letseat.RankByBayesian))
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	letseat "github.com/drewstinnett/letseat/pkg"
+	"github.com/spf13/cobra"
+)
+
+func newAttachCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "attach ENTRY FILE",
+		Short: "attach a photo or receipt to an entry",
+		Long: `Attach a photo or receipt to an entry. ENTRY is either the entry key, or
+the date and place of the entry, like "2023-12-14/Franks Place"`,
+		Args: cobra.ExactArgs(2),
+		RunE: runAttach,
+	}
+	cmd.Flags().String("kind", string(letseat.AttachmentPhoto), "What the file is (photo, receipt)")
+	return cmd
+}
+
+func runAttach(cmd *cobra.Command, args []string) error {
+	kind, err := letseat.ParseAttachmentKind(mustGetCmd[string](*cmd, "kind"))
+	if err != nil {
+		return err
+	}
+	diary := letseat.New(
+		letseat.WithDBFilename(mustGetCmd[string](*cmd, "data")),
+	)
+	defer dclose(diary)
+
+	e, err := findEntry(diary, args[0])
+	if err != nil {
+		return err
+	}
+	a, err := attachmentStore(mustGetCmd[string](*cmd, "data")).Add(args[1], kind)
+	if err != nil {
+		return err
+	}
+	if _, err := diary.Attach(e.Key(), *a); err != nil {
+		return err
+	}
+	slog.Info("attached!", "entry", e.Key(), "hash", a.Hash)
+	return nil
+}
+
+// attachmentStore returns the store holding the attachments for the given
+// database. Every database gets its own, so cleaning up after one can't remove
+// files another still needs. The default database keeps the usual store in the
+// data directory
+func attachmentStore(data string) *letseat.AttachmentStore {
+	if filepath.Clean(data) == filepath.Clean(config.DataFile) {
+		return letseat.NewAttachmentStore(config.AttachmentPath)
+	}
+	return letseat.NewAttachmentStore(strings.TrimSuffix(data, filepath.Ext(data)) + "-attachments")
+}
+
+// findEntry looks up an entry by its key, or by "YYYY-MM-DD/Place"
+func findEntry(diary *letseat.Diary, ref string) (*letseat.Entry, error) {
+	if e, err := diary.Get(ref); err == nil {
+		return e, nil
+	}
+	day, place, ok := strings.Cut(ref, "/")
+	if !ok {
+		return nil, fmt.Errorf("entry not found: %v", ref)
+	}
+	if err := validateDate(day); err != nil {
+		return nil, err
+	}
+	matches := letseat.Entries{}
+	for _, e := range diary.Entries() {
+		if e.Date != nil && e.Date.Format("2006-01-02") == day && strings.EqualFold(e.Place, place) {
+			matches = append(matches, e)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("entry not found: %v", ref)
+	case 1:
+		return &matches[0], nil
+	default:
+		return nil, fmt.Errorf("more than one entry matches %v, use the entry key instead", ref)
+	}
+}
+
+func newGCCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "remove attachment files that no entry refers to anymore",
+		RunE:  runGC,
+	}
+	cmd.Flags().Bool("dry-run", false, "Only show what would be removed")
+	return cmd
+}
+
+func runGC(cmd *cobra.Command, args []string) error {
+	diary := letseat.New(
+		letseat.WithDBFilename(mustGetCmd[string](*cmd, "data")),
+	)
+	defer dclose(diary)
+	entries, err := diary.AllEntries()
+	if err != nil {
+		return err
+	}
+	store := attachmentStore(mustGetCmd[string](*cmd, "data"))
+	var orphans []string
+	if mustGetCmd[bool](*cmd, "dry-run") {
+		orphans, err = store.Orphans(entries)
+	} else {
+		orphans, err = store.GC(entries)
+	}
+	if err != nil {
+		return err
+	}
+	for _, h := range orphans {
+		fmt.Fprintln(cmd.OutOrStdout(), h)
+	}
+	slog.Info("garbage collected", "orphans", len(orphans))
+	return nil
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGCOtherDB(t *testing.T) {
+	dir := t.TempDir()
+	mine, other := path.Join(dir, "mine.db"), path.Join(dir, "other.db")
+	receipt := path.Join(t.TempDir(), "receipt.txt")
+	require.NoError(t, os.WriteFile(receipt, []byte("wings: $18"), 0o600))
+
+	cmd := newRootCmd()
+	cmd.SetArgs([]string{"import", "../testdata/import.yaml", "--data", mine})
+	require.NoError(t, cmd.Execute())
+	cmd = newRootCmd()
+	cmd.SetArgs([]string{"attach", "2023-12-21/Biggy Wings", receipt, "--kind", "receipt", "--data", mine})
+	require.NoError(t, cmd.Execute())
+	hashes, err := attachmentStore(mine).Hashes()
+	require.NoError(t, err)
+	require.Len(t, hashes, 1)
+
+	// other.db doesn't refer to the receipt, so it must not be able to remove it
+	cmd = newRootCmd()
+	cmd.SetArgs([]string{"gc", "--data", other})
+	require.NoError(t, cmd.Execute())
+	got, err := attachmentStore(mine).Hashes()
+	require.NoError(t, err)
+	require.Equal(t, hashes, got)
+	require.NotEqual(t, attachmentStore(mine).Dir(), attachmentStore(other).Dir())
+}
@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/drewstinnett/gout/v2"
+	letseat "github.com/drewstinnett/letseat/pkg"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 func newConfigCmd() *cobra.Command {
@@ -20,3 +24,46 @@ func runConfig(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// getOrigin returns the coordinates of a named origin, like "home", from the config file
+func getOrigin(name string) (*letseat.Coordinates, error) {
+	origins := map[string]letseat.Coordinates{}
+	if err := viper.UnmarshalKey("origins", &origins); err != nil {
+		return nil, err
+	}
+	o, ok := origins[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown origin: %v, add it to the origins in your config file", name)
+	}
+	return &o, nil
+}
+
+// getTierNames returns what each place tier means, from the config file
+func getTierNames() (letseat.TierNames, error) {
+	if !viper.IsSet("tiers") {
+		return letseat.DefaultTierNames, nil
+	}
+	ret := letseat.TierNames{}
+	if err := viper.UnmarshalKey("tiers", &ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// getOccasions returns which tiers suit which occasions, from the config file
+func getOccasions() (letseat.Occasions, error) {
+	if !viper.IsSet("occasions") {
+		return letseat.DefaultOccasions, nil
+	}
+	ret := letseat.Occasions{}
+	if err := viper.UnmarshalKey("occasions", &ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// getNewPlacesGoal returns how many new places to try each year, from the
+// config file. 0 means there's no goal
+func getNewPlacesGoal() int {
+	return viper.GetInt("goals.new-places")
+}
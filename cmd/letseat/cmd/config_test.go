@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	letseat "github.com/drewstinnett/letseat/pkg"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetOrigin(t *testing.T) {
+	viper.Set("origins", map[string]any{
+		"home": map[string]any{"lat": 42.2808, "long": -83.7430},
+	})
+	t.Cleanup(viper.Reset)
+
+	got, err := getOrigin("home")
+	require.NoError(t, err)
+	require.Equal(t, &letseat.Coordinates{Lat: 42.2808, Long: -83.7430}, got)
+
+	_, err = getOrigin("work")
+	require.EqualError(t, err, "unknown origin: work, add it to the origins in your config file")
+}
+
+func TestGetTierNames(t *testing.T) {
+	t.Cleanup(viper.Reset)
+	got, err := getTierNames()
+	require.NoError(t, err)
+	require.Equal(t, letseat.DefaultTierNames, got)
+
+	viper.Set("tiers", map[string]any{"1": "cheap eats", "2": "splurge"})
+	got, err = getTierNames()
+	require.NoError(t, err)
+	require.Equal(t, letseat.TierNames{1: "cheap eats", 2: "splurge"}, got)
+
+	viper.Set("occasions", map[string]any{"payday": []string{"splurge"}})
+	occasions, err := getOccasions()
+	require.NoError(t, err)
+	tiers, err := occasions.Tiers("payday", got)
+	require.NoError(t, err)
+	require.Equal(t, []int{2}, tiers)
+}
+
+func TestApplyPreset(t *testing.T) {
+	viper.Set("presets", map[string]any{
+		"weekend-takeout": map[string]any{"mode": "takeout", "weekdays": []any{"fri", "sat"}, "earliest": "180d"},
+		"broken":          map[string]any{"flavor": "spicy"},
+	})
+	t.Cleanup(viper.Reset)
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		bindFilter(cmd)
+		cmd.Flags().String("current-date", "2024-03-13", "")
+		require.NoError(t, cmd.ParseFlags(args))
+		return cmd
+	}
+
+	cmd := newCmd("--preset", "weekend-takeout", "--earliest", "30d")
+	require.NoError(t, applyPreset(cmd))
+	f, err := newEntryFilterWithCmd(cmd)
+	require.NoError(t, err)
+	require.Equal(t, []letseat.Mode{letseat.ModeTakeout}, f.Modes)
+	require.Equal(t, []time.Weekday{time.Friday, time.Saturday}, f.Weekdays)
+	require.Equal(t, "2024-02-12", f.Earliest.Format("2006-01-02"), "flags on the command line win")
+
+	require.EqualError(t, applyPreset(newCmd("--preset", "broken")), "unknown setting in preset broken: flavor")
+	require.EqualError(t, applyPreset(newCmd("--preset", "nope")), "unknown preset: nope, add it to the presets in your config file")
+	require.NoError(t, applyPreset(newCmd()))
+}
+
+func TestNewNormalizationWithCmd(t *testing.T) {
+	t.Cleanup(viper.Reset)
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		bindNormalize(cmd)
+		require.NoError(t, cmd.ParseFlags(args))
+		return cmd
+	}
+	got, err := newNormalizationWithCmd(newCmd())
+	require.NoError(t, err)
+	require.Equal(t, letseat.NormalizeNone, got)
+
+	viper.Set("normalize", "zscore")
+	got, err = newNormalizationWithCmd(newCmd())
+	require.NoError(t, err)
+	require.Equal(t, letseat.NormalizeZScore, got, "config file sets the default")
+
+	got, err = newNormalizationWithCmd(newCmd("--normalize", "mean"))
+	require.NoError(t, err)
+	require.Equal(t, letseat.NormalizeMean, got, "flag wins over the config file")
+
+	_, err = newNormalizationWithCmd(newCmd("--normalize", "vibes"))
+	require.EqualError(t, err, "unknown normalization: vibes, must be one of none, mean or zscore")
+}
+
+func TestGetNewPlacesGoal(t *testing.T) {
+	t.Cleanup(viper.Reset)
+	require.Equal(t, 0, getNewPlacesGoal())
+	viper.Set("goals", map[string]any{"new-places": 12})
+	require.Equal(t, 12, getNewPlacesGoal())
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	letseat "github.com/drewstinnett/letseat/pkg"
+	"github.com/spf13/cobra"
+)
+
+func newCostCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "cost",
+		Aliases: []string{"spend"},
+		Short:   "Show what was spent, by place, month, week and mode",
+		RunE:    runCost,
+	}
+	bindFilter(cmd)
+	return cmd
+}
+
+func runCost(cmd *cobra.Command, args []string) error {
+	f, err := newEntryFilterWithCmd(cmd)
+	if err != nil {
+		return err
+	}
+	diary := letseat.New(
+		letseat.WithFilter(*f),
+		letseat.WithDBFilename(mustGetCmd[string](*cmd, "data")),
+	)
+	defer dclose(diary)
+	entries := diary.Entries()
+	return g.Print(entries.CostReport())
+}
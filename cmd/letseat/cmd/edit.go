@@ -55,8 +55,12 @@ func runEdit(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	places, err := diary.Places()
+	if err != nil {
+		return err
+	}
 	editForm := newEntryForm(e)
-	if err := editForm.NewForm(diary.Entries()).Run(); err != nil {
+	if err := editForm.NewForm(diary.Entries(), places).Run(); err != nil {
 		return err
 	}
 
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	letseat "github.com/drewstinnett/letseat/pkg"
+	"github.com/spf13/cobra"
+)
+
+// periodLayouts are the periods exploration can be broken down by
+var periodLayouts = map[string]string{
+	"month": "2006-01",
+	"year":  "2006",
+}
+
+func newExploreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "explore",
+		Short: "Show how many new places you've tried, compared with going back to old favorites",
+		RunE:  runExplore,
+	}
+	bindFilter(cmd)
+	cmd.Flags().String("by", "month", "Period to break visits down by (month or year)")
+	return cmd
+}
+
+func runExplore(cmd *cobra.Command, args []string) error {
+	by := mustGetCmd[string](*cmd, "by")
+	layout, ok := periodLayouts[by]
+	if !ok {
+		return fmt.Errorf("unknown period: %v, must be one of month or year", by)
+	}
+	defaultEarliest(cmd, "1y")
+	f, err := newEntryFilterWithCmd(cmd)
+	if err != nil {
+		return err
+	}
+	diary := letseat.New(
+		letseat.WithFilter(*f),
+		letseat.WithDBFilename(mustGetCmd[string](*cmd, "data")),
+	)
+	defer dclose(diary)
+
+	rows := []string{}
+	if goal := getNewPlacesGoal(); goal > 0 {
+		rows = append(rows, titleStyle.Render(diary.NewPlacesGoal(goal, getCurrentDate(cmd)).String()))
+	}
+	rows = append(rows, listHeader("\nNew vs Repeat Visits"))
+	for _, p := range diary.Exploration(layout) {
+		rows = append(rows, listItem(fmt.Sprintf("%8v %v %v", p.Period, explorationBar(p), p)))
+	}
+	fmt.Fprint(cmd.OutOrStdout(), docStyle.Render(lipgloss.JoinVertical(lipgloss.Left, rows...)))
+	return nil
+}
+
+// explorationBar shows the share of new visits filled in, and repeats as the rest of the bar
+func explorationBar(p letseat.ExplorationPeriod) string {
+	filled := int(p.Ratio*barWidth + 0.5)
+	return barStyle.Render(strings.Repeat("█", filled)) + helpStyle(strings.Repeat("░", barWidth-filled))
+}
@@ -1,7 +1,16 @@
 package cmd
 
 import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
 
 	letseat "github.com/drewstinnett/letseat/pkg"
 	"github.com/spf13/cobra"
@@ -13,11 +22,18 @@ func newExportCmd() *cobra.Command {
 		Short: "export entries",
 		RunE:  runExport,
 	}
+	bindWhere(cmd)
+	cmd.Flags().String("archive", "", "Write a .tar.gz backup with the entries and all their attachments to this file")
 	return cmd
 }
 
 func runExport(cmd *cobra.Command, args []string) error {
+	where, err := whereFlag(cmd)
+	if err != nil {
+		return err
+	}
 	diary := letseat.New(
+		letseat.WithFilter(letseat.EntryFilter{Where: where}),
 		letseat.WithDBFilename(mustGetCmd[string](*cmd, "data")),
 	)
 	defer dclose(diary)
@@ -25,6 +41,67 @@ func runExport(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if archive := mustGetCmd[string](*cmd, "archive"); archive != "" {
+		return writeArchive(archive, out, diary.Entries(), attachmentStore(mustGetCmd[string](*cmd, "data")))
+	}
 	fmt.Fprint(cmd.OutOrStdout(), string(out))
 	return nil
 }
+
+// writeArchive writes the exported diary, along with every attachment the
+// entries refer to, in to a gzipped tarball
+func writeArchive(fn string, diary []byte, entries letseat.Entries, store *letseat.AttachmentStore) (err error) {
+	f, err := os.Create(filepath.Clean(fn))
+	if err != nil {
+		return err
+	}
+	// A failed close can mean a truncated archive, so it has to fail the export
+	defer func() {
+		err = errors.Join(err, f.Close())
+	}()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	now := time.Now()
+	if err := tw.WriteHeader(&tar.Header{Name: "diary.yaml", Mode: 0o600, Size: int64(len(diary)), ModTime: now}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(diary); err != nil {
+		return err
+	}
+
+	seen := map[string]bool{}
+	for _, entry := range entries {
+		for _, a := range entry.Attachments {
+			if seen[a.Hash] {
+				continue
+			}
+			seen[a.Hash] = true
+			if err := addArchiveFile(tw, store.Path(a), path.Join("attachments", filepath.ToSlash(store.RelPath(a.Hash)))); err != nil {
+				slog.Warn("skipping attachment", "hash", a.Hash, "error", err)
+			}
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func addArchiveFile(tw *tar.Writer, src, name string) error {
+	f, err := os.Open(filepath.Clean(src))
+	if err != nil {
+		return err
+	}
+	defer dclose(f)
+	st, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: st.Size(), ModTime: st.ModTime()}); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path"
+	"testing"
+
+	letseat "github.com/drewstinnett/letseat/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteArchive(t *testing.T) {
+	src := path.Join(t.TempDir(), "receipt.txt")
+	require.NoError(t, os.WriteFile(src, []byte("tacos: $12"), 0o600))
+	store := letseat.NewAttachmentStore(path.Join(t.TempDir(), "attachments"))
+	a, err := store.Add(src, letseat.AttachmentReceipt)
+	require.NoError(t, err)
+
+	fn := path.Join(t.TempDir(), "backup.tar.gz")
+	require.NoError(t, writeArchive(
+		fn,
+		[]byte("- place: Mamacitas\n"),
+		letseat.Entries{{Place: "Mamacitas", Attachments: []letseat.Attachment{*a, *a}}},
+		store,
+	))
+
+	f, err := os.Open(fn)
+	require.NoError(t, err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+	names := []string{}
+	for {
+		h, err := tr.Next()
+		if err != nil {
+			break
+		}
+		names = append(names, h.Name)
+	}
+	require.Equal(t, []string{"diary.yaml", path.Join("attachments", a.Hash[:2], a.Hash)}, names)
+}
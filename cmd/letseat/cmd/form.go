@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"fmt"
-	"strconv"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/huh"
@@ -12,29 +14,57 @@ import (
 func (e entryForm) Entry() letseat.Entry {
 	d, err := time.Parse("2006-01-02", e.date)
 	panicIfErr(err)
-
-	cost, err := strconv.Atoi(e.cost)
-	panicIfErr(err)
+	if e.clock != "" {
+		c, err := letseat.ParseClock(e.clock)
+		panicIfErr(err)
+		d = d.Add(c.Duration())
+	}
 
 	ret := letseat.Entry{
-		Place:     e.place,
-		Date:      &d,
-		IsTakeout: e.takeout,
-		Ratings:   make(map[string]int, len(e.ratings)),
-		Cost:      cost,
+		Place:   e.place,
+		Date:    &d,
+		Meal:    e.meal,
+		Mode:    e.mode,
+		Ratings: make(map[string]int, len(e.ratings)),
+		Cost: letseat.Bill{
+			Subtotal: e.mustParseMoney(e.subtotal),
+			Tax:      e.mustParseMoney(e.tax),
+			Tip:      e.mustParseMoney(e.tip),
+		},
 	}
 
 	if e.newPlace != "" {
 		ret.Place = e.newPlace
 	}
-	for person, rating := range e.ratings {
-		ret.Ratings[person] = *rating
+	if e.mode == letseat.ModeDelivery {
+		ret.Platform = e.platform
+		ret.Cost.DeliveryFee = e.mustParseMoney(e.deliveryFee)
+	}
+	if len(e.attendees) > 0 {
+		ret.Attendees = slices.Clone(e.attendees)
+	}
+	ret.Guests = splitNames(e.guests)
+	for _, person := range e.attendees {
+		if rating, ok := e.ratings[person]; ok && *rating != 0 {
+			ret.Ratings[person] = *rating
+		}
 	}
 	return ret
 }
 
-func (e *entryForm) NewForm(entries letseat.Entries) *huh.Form {
-	placeOpts := newPlaceOpts(entries.UniquePlaceNames())
+// splitNames splits up a comma separated list of names
+func splitNames(s string) []string {
+	var ret []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			ret = append(ret, item)
+		}
+	}
+	return ret
+}
+
+func (e *entryForm) NewForm(entries letseat.Entries, places letseat.Places) *huh.Form {
+	placeOpts := newPlaceOpts(e.openPlaceNames(entries, places))
 
 	groups := []*huh.Group{
 		huh.NewGroup(
@@ -43,21 +73,20 @@ func (e *entryForm) NewForm(entries letseat.Entries) *huh.Form {
 				Description("When did you go?").
 				Validate(validateDate).
 				Value(&e.date),
+			huh.NewInput().
+				Title("Time").
+				Description("What time did you eat? (optional, like 18:30)").
+				Validate(validateClock).
+				Value(&e.clock),
+			huh.NewSelect[letseat.MealType]().
+				Title("Meal").
+				Options(huh.NewOptions(letseat.AllMealTypes...)...).
+				Value(&e.meal),
 			huh.NewSelect[string]().
 				Title("Place").
 				Description("What's this place called?").
 				Options(placeOpts...).
 				Value(&e.place),
-			huh.NewInput().
-				Title("Cost").
-				Description("Use 0 for unknown cost").
-				Placeholder("0").
-				Validate(validateNumber).
-				Prompt("$ ").
-				Value(&e.cost),
-			huh.NewConfirm().
-				Title("Take Out?").
-				Value(&e.takeout),
 		),
 		huh.NewGroup(
 			huh.NewInput().
@@ -69,10 +98,26 @@ func (e *entryForm) NewForm(entries letseat.Entries) *huh.Form {
 			return e.place != ""
 		}),
 	}
-	ri := e.newRatingInputs(entries.PeopleEnhanced())
-	if len(ri) > 0 {
-		groups = append(groups, huh.NewGroup(ri...))
-	}
+	groups = append(groups, e.newModeGroups(places)...)
+	groups = append(groups,
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Platform").
+				Description("What delivered it? (doordash, ubereats, etc)").
+				Value(&e.platform),
+			e.newMoneyInput("Delivery Fee", "Any fees the platform charged", &e.deliveryFee),
+		).WithHideFunc(func() bool {
+			return e.mode != letseat.ModeDelivery
+		}),
+		huh.NewGroup(
+			e.newMoneyInput("Subtotal", "Cost of the food, use 0 for unknown cost", &e.subtotal),
+			e.newMoneyInput("Tax", "", &e.tax),
+			e.newMoneyInput("Tip", "", &e.tip),
+		),
+	)
+	people := entries.PeopleEnhanced()
+	groups = append(groups, e.newAttendeeGroup(people))
+	groups = append(groups, e.newRatingGroups(people)...)
 	return huh.NewForm(groups...)
 }
 
@@ -97,6 +142,107 @@ func ratingOptionsWithSelected(s int) []huh.Option[int] {
 	return ret
 }
 
+func (e *entryForm) newMoneyInput(title, desc string, v *string) *huh.Input {
+	return huh.NewInput().
+		Title(title).
+		Description(desc).
+		Placeholder("0.00").
+		Validate(func(s string) error {
+			_, err := e.parseMoney(s)
+			return err
+		}).
+		Prompt(fmt.Sprintf("%v ", e.currency)).
+		Value(v)
+}
+
+// parseMoney parses an amount typed in to the form, treating blank as zero
+func (e entryForm) parseMoney(s string) (letseat.Money, error) {
+	if strings.TrimSpace(s) == "" {
+		return letseat.NewMoney(0, e.currency), nil
+	}
+	return letseat.ParseMoney(s, e.currency)
+}
+
+func (e entryForm) mustParseMoney(s string) letseat.Money {
+	m, err := e.parseMoney(s)
+	panicIfErr(err)
+	return m
+}
+
+func newModeOpts(modes []letseat.Mode) []huh.Option[letseat.Mode] {
+	ret := make([]huh.Option[letseat.Mode], len(modes))
+	for idx, item := range modes {
+		ret[idx] = huh.NewOption(item.String(), item)
+	}
+	return ret
+}
+
+// newModeGroups returns a group for each distinct set of modes that the known
+// places support. Only the group matching the chosen place is shown, so the
+// choices are limited to what the place actually does
+func (e *entryForm) newModeGroups(places letseat.Places) []*huh.Group {
+	modeSets := map[string][]letseat.Mode{
+		modesKey(letseat.AllModes): letseat.AllModes,
+	}
+	for _, place := range places {
+		modes := place.Format.Modes()
+		modeSets[modesKey(modes)] = modes
+	}
+	keys := make([]string, 0, len(modeSets))
+	for k := range modeSets {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	groups := make([]*huh.Group, len(keys))
+	for idx, k := range keys {
+		k := k
+		groups[idx] = huh.NewGroup(
+			huh.NewSelect[letseat.Mode]().
+				Title("Service").
+				Description("How did you get your food?").
+				Options(newModeOpts(modeSets[k])...).
+				Value(&e.mode),
+		).WithHideFunc(func() bool {
+			return modesKey(e.placeModes(places)) != k
+		})
+	}
+	return groups
+}
+
+// placeModes returns the modes supported by the place currently chosen in the form
+func (e entryForm) placeModes(places letseat.Places) []letseat.Mode {
+	name := e.place
+	if name == "" {
+		name = e.newPlace
+	}
+	if p := places.Find(name); p != nil {
+		return p.Format.Modes()
+	}
+	return letseat.AllModes
+}
+
+// openPlaceNames returns the places you can still go to. Closed places are left
+// out, unless the entry is already at one
+func (e entryForm) openPlaceNames(entries letseat.Entries, places letseat.Places) []string {
+	ret := []string{}
+	for _, name := range entries.UniquePlaceNames() {
+		if p := places.Find(name); p != nil && p.IsClosed() && name != e.place {
+			continue
+		}
+		ret = append(ret, name)
+	}
+	return ret
+}
+
+func modesKey(modes []letseat.Mode) string {
+	ret := make([]string, len(modes))
+	for idx, item := range modes {
+		ret[idx] = item.String()
+	}
+	return strings.Join(ret, ",")
+}
+
 func newPlaceOpts(places []string) []huh.Option[string] {
 	placeOpts := make([]huh.Option[string], len(places)+1)
 	placeOpts[0] = huh.Option[string]{
@@ -116,9 +262,11 @@ func newPlaceOpts(places []string) []huh.Option[string] {
 func newEntryForm(t *letseat.Entry) entryForm {
 	if t == nil {
 		return entryForm{
-			date:    time.Now().Format("2006-01-02"),
-			cost:    "0",
-			ratings: map[string]*int{},
+			date:     time.Now().Format("2006-01-02"),
+			meal:     letseat.MealTypeAt(time.Now()),
+			currency: letseat.DefaultCurrency,
+			mode:     letseat.ModeDineIn,
+			ratings:  map[string]*int{},
 		}
 	}
 	ratings := map[string]*int{}
@@ -126,24 +274,85 @@ func newEntryForm(t *letseat.Entry) entryForm {
 		v := v
 		ratings[k] = &v
 	}
+	attendees := []string{}
+	for _, name := range t.Diners() {
+		if !slices.Contains(t.Guests, name) {
+			attendees = append(attendees, name)
+		}
+	}
+	currency := letseat.DefaultCurrency
+	if total, err := t.Cost.Total(); err == nil && !total.IsZero() {
+		currency = total.Currency
+	}
+	var clock string
+	if t.HasTime() {
+		clock = t.Date.Format("15:04")
+	}
+	meal := t.MealType()
+	if meal == "" {
+		meal = letseat.MealDinner
+	}
 	return entryForm{
-		date:    t.Date.Format("2006-01-02"),
-		cost:    fmt.Sprint(t.Cost),
-		ratings: ratings,
-		place:   t.Place,
-		takeout: t.IsTakeout,
+		date:        t.Date.Format("2006-01-02"),
+		clock:       clock,
+		meal:        meal,
+		currency:    currency,
+		subtotal:    moneyValue(t.Cost.Subtotal),
+		tax:         moneyValue(t.Cost.Tax),
+		tip:         moneyValue(t.Cost.Tip),
+		deliveryFee: moneyValue(t.Cost.DeliveryFee),
+		attendees:   attendees,
+		guests:      strings.Join(t.Guests, ", "),
+		ratings:     ratings,
+		place:       t.Place,
+		mode:        t.Mode,
+		platform:    t.Platform,
+	}
+}
+
+// moneyValue returns the amount to pre-fill in a form, leaving zero blank
+func moneyValue(m letseat.Money) string {
+	if m.IsZero() {
+		return ""
 	}
+	return m.Decimal()
 }
 
-func (e *entryForm) newRatingInputs(people []letseat.Person) []huh.Field {
-	ratingInputs := make([]huh.Field, len(people))
+func (e *entryForm) newAttendeeGroup(people []letseat.Person) *huh.Group {
+	opts := make([]huh.Option[string], len(people))
 	for idx, item := range people {
-		e.ratings[item.Name] = toPTR(0)
-		ro := ratingOptionsWithSelected(*e.ratings[item.Name])
-		ratingInputs[idx] = huh.NewSelect[int]().
-			Title(fmt.Sprintf("%v's Rating (%v)", item.Name, *e.ratings[item.Name])).
-			Options(ro...).
-			Value(e.ratings[item.Name])
-	}
-	return ratingInputs
+		opts[idx] = huh.NewOption(item.Name, item.Name)
+	}
+	fields := []huh.Field{}
+	if len(opts) > 0 {
+		fields = append(fields, huh.NewMultiSelect[string]().
+			Title("Who went?").
+			Options(opts...).
+			Value(&e.attendees))
+	}
+	fields = append(fields, huh.NewInput().
+		Title("Guests").
+		Description("Anyone else along for just this meal, separated by commas").
+		Value(&e.guests))
+	return huh.NewGroup(fields...)
+}
+
+// newRatingGroups asks each person for a rating, but only if they were there
+func (e *entryForm) newRatingGroups(people []letseat.Person) []*huh.Group {
+	groups := make([]*huh.Group, len(people))
+	for idx, item := range people {
+		name := item.Name
+		if _, ok := e.ratings[name]; !ok {
+			e.ratings[name] = toPTR(0)
+		}
+		groups[idx] = huh.NewGroup(
+			huh.NewSelect[int]().
+				Title(fmt.Sprintf("%v's Rating", name)).
+				Options(ratingOptionsWithSelected(*e.ratings[name])...).
+				Value(e.ratings[name]),
+		).WithHideFunc(func() bool {
+			return !slices.Contains(e.attendees, name)
+		})
+	}
+	return groups
 }
@@ -1,9 +1,11 @@
 package cmd
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/charmbracelet/huh"
+	letseat "github.com/drewstinnett/letseat/pkg"
 	"github.com/stretchr/testify/require"
 )
 
@@ -19,3 +21,83 @@ func TestNewPlaceOpts(t *testing.T) {
 	require.Contains(t, got, "> Someplace New!", "Make sure the default is something new")
 	require.Contains(t, got, "Taco Tuesday", "Make sure we still have Taco Tuesday")
 }
+
+func TestOpenPlaceNames(t *testing.T) {
+	gone := letseat.MustNewPlace(letseat.WithName("Gone Burger"))
+	gone.Close(false)
+	places := letseat.Places{*gone, *letseat.MustNewPlace(letseat.WithName("Taco Tuesday"))}
+	entries := letseat.Entries{{Place: "Gone Burger"}, {Place: "Taco Tuesday"}, {Place: "Unknown Diner"}}
+
+	e := newEntryForm(nil)
+	require.Equal(t, []string{"Taco Tuesday", "Unknown Diner"}, e.openPlaceNames(entries, places))
+	e.place = "Gone Burger"
+	require.Equal(t, []string{"Gone Burger", "Taco Tuesday", "Unknown Diner"}, e.openPlaceNames(entries, places), "editing an entry keeps its place")
+}
+
+func TestPlaceModes(t *testing.T) {
+	places := letseat.Places{
+		*letseat.MustNewPlace(letseat.WithName("Taco Truck"), letseat.WithFormat(letseat.Format{FoodTruck: true})),
+		*letseat.MustNewPlace(letseat.WithName("Mystery Spot")),
+	}
+	e := newEntryForm(nil)
+	e.place = "Taco Truck"
+	require.Equal(t, []letseat.Mode{letseat.ModeFoodTruck}, e.placeModes(places))
+	e.place = "Mystery Spot"
+	require.Equal(t, letseat.AllModes, e.placeModes(places))
+	e.place = ""
+	e.newPlace = "Brand New"
+	require.Equal(t, letseat.AllModes, e.placeModes(places))
+	require.Len(t, e.newModeGroups(places), 2, "one group for every distinct set of modes")
+}
+
+func TestEntryFormMoney(t *testing.T) {
+	e := newEntryForm(nil)
+	e.place = "Taco Tuesday"
+	e.subtotal = "23.45"
+	e.tip = "$4"
+	got, err := e.Entry().Cost.Total()
+	require.NoError(t, err)
+	require.Equal(t, "$27.45", got.String())
+	require.True(t, e.Entry().Cost.DeliveryFee.IsZero(), "only delivery has a delivery fee")
+
+	e.mode = letseat.ModeDelivery
+	e.deliveryFee = "3.99"
+	got, err = e.Entry().Cost.Total()
+	require.NoError(t, err)
+	require.Equal(t, "$31.44", got.String())
+
+	_, err = e.parseMoney("12.345")
+	require.EqualError(t, err, `too many decimal places for USD: "12.345"`)
+}
+
+func TestEntryFormAttendees(t *testing.T) {
+	e := newEntryForm(nil)
+	e.place = "Taco Tuesday"
+	e.attendees = []string{"andrei"}
+	e.guests = " grandma, ,cousin eddie"
+	e.newRatingGroups([]letseat.Person{{Name: "andrei"}, {Name: "jeymes"}})
+	*e.ratings["andrei"] = 4
+	*e.ratings["jeymes"] = 5
+
+	got := e.Entry()
+	require.Equal(t, []string{"andrei"}, got.Attendees)
+	require.Equal(t, []string{"grandma", "cousin eddie"}, got.Guests)
+	require.Equal(t, map[string]int{"andrei": 4}, got.Ratings, "only people who went get to rate")
+
+	edit := newEntryForm(&got)
+	require.Equal(t, []string{"andrei"}, edit.attendees)
+	require.Equal(t, "grandma, cousin eddie", edit.guests)
+}
+
+func TestEntryFormTime(t *testing.T) {
+	e := newEntryForm(nil)
+	e.place = "Taco Tuesday"
+	e.date = "2024-01-06"
+	require.True(t, strings.HasPrefix(e.Entry().Key(), "/2024-01-06T00:00:00"))
+	e.clock = "10:45"
+	e.meal = letseat.MealBreakfast
+	got := e.Entry()
+	require.Equal(t, "2024-01-06 10:45", got.Date.Format("2006-01-02 15:04"))
+	require.Equal(t, letseat.MealBreakfast, got.Meal)
+	require.Equal(t, "10:45", newEntryForm(&got).clock)
+}
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	letseat "github.com/drewstinnett/letseat/pkg"
+	"github.com/spf13/cobra"
+)
+
+// heatColors go from an empty day up to the busiest days
+var heatColors = []lipgloss.AdaptiveColor{
+	{Light: "#EBEDF0", Dark: "#2D333B"},
+	{Light: "#9BE9A8", Dark: "#0E4429"},
+	{Light: "#40C463", Dark: "#006D32"},
+	{Light: "#30A14E", Dark: "#26A641"},
+	{Light: "#216E39", Dark: "#39D353"},
+}
+
+func newHeatmapCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "heatmap",
+		Short: "Show a calendar of the days you ate out, over the last year unless dates are given",
+		RunE:  runHeatmap,
+	}
+	bindFilter(cmd)
+	cmd.Flags().String("metric", string(letseat.HeatmapVisits), "What to color each day by (visits, cost or rating)")
+	return cmd
+}
+
+func runHeatmap(cmd *cobra.Command, args []string) error {
+	metric, err := letseat.ParseHeatmapMetric(mustGetCmd[string](*cmd, "metric"))
+	if err != nil {
+		return err
+	}
+	// A year fits nicely across a terminal
+	defaultEarliest(cmd, "1y")
+	f, err := newEntryFilterWithCmd(cmd)
+	if err != nil {
+		return err
+	}
+	end := getCurrentDate(cmd)
+	if f.Latest != nil {
+		end = *f.Latest
+	}
+	// Only --latest was given, so show the year leading up to it
+	if f.Earliest == nil {
+		f.Earliest = toPTR(end.AddDate(-1, 0, 0))
+	}
+	start := *f.Earliest
+	diary := letseat.New(
+		letseat.WithFilter(*f),
+		letseat.WithDBFilename(mustGetCmd[string](*cmd, "data")),
+	)
+	defer dclose(diary)
+
+	entries := diary.Entries()
+	daily := entries.Daily(metric)
+	fmt.Fprint(cmd.OutOrStdout(), docStyle.Render(lipgloss.JoinVertical(lipgloss.Left,
+		titleStyle.Render(heatmapSummary(metric, entries, daily)),
+		"",
+		renderHeatmap(daily, start, end, metric),
+	)))
+	return nil
+}
+
+// renderHeatmap draws one column per week, with a row for each weekday starting on Monday
+func renderHeatmap(daily map[string]float64, start, end time.Time, metric letseat.HeatmapMetric) string {
+	start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	// Back up to the Monday, so the weeks line up
+	first := start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7))
+
+	var top float64
+	for _, v := range daily {
+		top = math.Max(top, v)
+	}
+	if metric == letseat.HeatmapRating {
+		// Ratings are colored on the star scale, not against the best day
+		top = 5
+	}
+
+	rows := make([]strings.Builder, 7)
+	for idx, label := range []string{"Mon", "", "Wed", "", "Fri", "", "Sun"} {
+		rows[idx].WriteString(fmt.Sprintf("%-4v", label))
+	}
+	for week := first; !week.After(end); week = week.AddDate(0, 0, 7) {
+		for idx := range rows {
+			d := week.AddDate(0, 0, idx)
+			if d.Before(start) || d.After(end) {
+				rows[idx].WriteString("  ")
+				continue
+			}
+			rows[idx].WriteString(heatCell(heatLevel(daily[d.Format("2006-01-02")], top)))
+		}
+	}
+	legend := strings.Builder{}
+	legend.WriteString("Less ")
+	for level := range heatColors {
+		legend.WriteString(heatCell(level))
+	}
+	legend.WriteString("More")
+
+	lines := []string{monthLabels(first, start, end)}
+	for _, r := range rows {
+		lines = append(lines, r.String())
+	}
+	lines = append(lines, "", helpStyle(legend.String()))
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// monthLabels puts the name of each month over the week it starts in. Weeks
+// are 2 wide and names are 3, so a name is skipped if there's no room
+func monthLabels(first, start, end time.Time) string {
+	ret := []rune(strings.Repeat(" ", 4))
+	col := 4
+	lastMonth := time.Month(0)
+	for week := first; !week.After(end); week = week.AddDate(0, 0, 7) {
+		m := week.Month()
+		if week.Before(start) {
+			m = start.Month()
+		}
+		if m != lastMonth && len(ret) <= col {
+			for len(ret) < col {
+				ret = append(ret, ' ')
+			}
+			ret = append(ret, []rune(time.Date(2000, m, 1, 0, 0, 0, 0, time.UTC).Format("Jan"))...)
+			lastMonth = m
+		}
+		col += 2
+	}
+	return string(ret)
+}
+
+// heatLevel buckets a value in to one of the heat colors, compared to the top value
+func heatLevel(v, top float64) int {
+	if v <= 0 || top <= 0 {
+		return 0
+	}
+	hottest := len(heatColors) - 1
+	return min(hottest, max(1, int(math.Ceil(v/top*float64(hottest)))))
+}
+
+func heatCell(level int) string {
+	return lipgloss.NewStyle().Foreground(heatColors[level]).Render("■") + " "
+}
+
+// heatmapSummary is the headline over the heatmap, like "42 meals out over 30 days"
+func heatmapSummary(metric letseat.HeatmapMetric, entries letseat.Entries, daily map[string]float64) string {
+	switch metric {
+	case letseat.HeatmapCost:
+		return fmt.Sprintf("%v spent over %v days", entries.CostReport().Total, len(daily))
+	case letseat.HeatmapRating:
+		if len(daily) == 0 {
+			return "No rated meals"
+		}
+		var total float64
+		for _, v := range daily {
+			total += v
+		}
+		return fmt.Sprintf("%.1f★ average over %v rated days", total/float64(len(daily)), len(daily))
+	default:
+		return fmt.Sprintf("%v meals out over %v days", len(entries), len(daily))
+	}
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	letseat "github.com/drewstinnett/letseat/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeatLevel(t *testing.T) {
+	require.Equal(t, 0, heatLevel(0, 4))
+	require.Equal(t, 1, heatLevel(0.1, 4))
+	require.Equal(t, 2, heatLevel(2, 4))
+	require.Equal(t, 4, heatLevel(4, 4))
+	require.Equal(t, 0, heatLevel(3, 0))
+}
+
+func TestRenderHeatmap(t *testing.T) {
+	start := time.Date(2024, 1, 3, 0, 0, 0, 0, time.Local)
+	end := time.Date(2024, 2, 29, 0, 0, 0, 0, time.Local)
+	got := renderHeatmap(map[string]float64{"2024-01-05": 2}, start, end, letseat.HeatmapVisits)
+	lines := strings.Split(got, "\n")
+	require.Len(t, lines, 10, "months, 7 weekdays, a gap and the legend")
+	require.True(t, strings.HasPrefix(lines[0], "    Jan"), lines[0])
+	require.Contains(t, lines[0], "Feb")
+	require.True(t, strings.HasPrefix(lines[1], "Mon "))
+}
@@ -22,17 +22,18 @@ func TestImport(t *testing.T) {
 		t,
 		`- place: Franks Place
   date: 2023-12-14T00:00:00Z
-  takeout: true
+  mode: takeout
   ratings:
     andrei: 4
     jeymes: 3
 - place: McDonuoughs Pub
   date: 2023-12-16T00:00:00Z
+  mode: dine-in
   ratings:
     andrei: 4
 - place: Biggy Wings
   date: 2023-12-21T00:00:00Z
-  takeout: true
+  mode: takeout
   ratings:
     andrei: 3
 `,
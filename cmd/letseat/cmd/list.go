@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	letseat "github.com/drewstinnett/letseat/pkg"
+	"github.com/spf13/cobra"
+)
+
+func newListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the entries matching the filter",
+		RunE:    runList,
+	}
+	bindFilter(cmd)
+	return cmd
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	f, err := newEntryFilterWithCmd(cmd)
+	if err != nil {
+		return err
+	}
+	diary := letseat.New(
+		letseat.WithFilter(*f),
+		letseat.WithDBFilename(mustGetCmd[string](*cmd, "data")),
+	)
+	defer dclose(diary)
+	return g.Print(diary.Entries())
+}
@@ -21,12 +21,21 @@ func newLogCmd() *cobra.Command {
 }
 
 type entryForm struct {
-	place    string
-	newPlace string
-	cost     string
-	date     string
-	takeout  bool
-	ratings  map[string]*int
+	place       string
+	newPlace    string
+	currency    string
+	subtotal    string
+	tax         string
+	tip         string
+	deliveryFee string
+	date        string
+	clock       string
+	meal        letseat.MealType
+	mode        letseat.Mode
+	platform    string
+	attendees   []string
+	guests      string
+	ratings     map[string]*int
 }
 
 func runLog(cmd *cobra.Command, args []string) error {
@@ -34,9 +43,13 @@ func runLog(cmd *cobra.Command, args []string) error {
 		letseat.WithFilter(*mustNewEntryFilterWithCmd(cmd)),
 		letseat.WithDBFilename(mustGetCmd[string](*cmd, "data")),
 	)
+	places, err := diary.Places()
+	if err != nil {
+		return err
+	}
 	e := newEntryForm(nil)
 
-	if err := e.NewForm(diary.Entries()).Run(); err != nil {
+	if err := e.NewForm(diary.Entries(), places).Run(); err != nil {
 		return err
 	}
 
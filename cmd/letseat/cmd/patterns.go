@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	letseat "github.com/drewstinnett/letseat/pkg"
+	"github.com/spf13/cobra"
+)
+
+// barWidth is how long the longest bar in a chart gets
+const barWidth = 30
+
+var barStyle = lipgloss.NewStyle().Foreground(highlight)
+
+func newPatternsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "patterns",
+		Short: "Show when you eat out, by weekday, month and season, and any habits",
+		RunE:  runPatterns,
+	}
+	bindFilter(cmd)
+	return cmd
+}
+
+func runPatterns(cmd *cobra.Command, args []string) error {
+	// Months and seasons need a full year to mean anything
+	defaultEarliest(cmd, "1y")
+	f, err := newEntryFilterWithCmd(cmd)
+	if err != nil {
+		return err
+	}
+	diary := letseat.New(
+		letseat.WithFilter(*f),
+		letseat.WithDBFilename(mustGetCmd[string](*cmd, "data")),
+	)
+	defer dclose(diary)
+	entries := diary.Entries()
+	if len(entries) == 0 {
+		return fmt.Errorf("no entries found! Try adding some with %v log", os.Args[0])
+	}
+	p := entries.Patterns()
+
+	sections := []string{
+		barChart("By Weekday", p.Weekdays),
+		barChart("By Month", p.Months),
+		barChart("By Season", p.Seasons),
+	}
+	habits := []string{listHeader("\nHabits")}
+	for _, h := range p.Habits {
+		habits = append(habits, listItem(h.String()))
+	}
+	if len(p.Habits) == 0 {
+		habits = append(habits, listItem("No habits yet"))
+	}
+	sections = append(sections, lipgloss.JoinVertical(lipgloss.Left, habits...))
+	fmt.Fprint(cmd.OutOrStdout(), docStyle.Render(lipgloss.JoinVertical(lipgloss.Left, sections...)))
+	return nil
+}
+
+// barChart draws a bar for the visits in each bucket, with the spend and
+// average rating alongside
+func barChart(title string, buckets []letseat.PatternBucket) string {
+	top := 0
+	for _, b := range buckets {
+		top = max(top, b.Visits)
+	}
+	rows := []string{listHeader("\n" + title)}
+	for _, b := range buckets {
+		bar := ""
+		if top > 0 {
+			bar = strings.Repeat("█", b.Visits*barWidth/top)
+		}
+		row := fmt.Sprintf("%10v %v%v %3v", b.Label, barStyle.Render(bar), strings.Repeat(" ", barWidth-len([]rune(bar))), b.Visits)
+		if !b.Spent.IsZero() {
+			row += fmt.Sprintf(" %10v", b.Spent)
+		} else {
+			row += strings.Repeat(" ", 11)
+		}
+		if b.AverageRating > 0 {
+			row += fmt.Sprintf(" %.1f★", b.AverageRating)
+		}
+		rows = append(rows, listItem(row))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	letseat "github.com/drewstinnett/letseat/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBarChart(t *testing.T) {
+	got := barChart("By Season", []letseat.PatternBucket{
+		{Label: "winter", Visits: 10, Spent: letseat.MustParseMoney("120", "USD"), AverageRating: 4.25},
+		{Label: "spring", Visits: 5},
+		{Label: "summer"},
+	})
+	lines := strings.Split(got, "\n")
+	require.Len(t, lines, 6, "a gap, the title, its underline and a row for each bucket")
+	require.Contains(t, lines[3], strings.Repeat("█", barWidth)+"  10    $120.00 4.2★")
+	require.Contains(t, lines[4], strings.Repeat("█", barWidth/2)+" ")
+	require.NotContains(t, lines[5], "█")
+}
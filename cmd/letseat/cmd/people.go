@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	letseat "github.com/drewstinnett/letseat/pkg"
+	"github.com/spf13/cobra"
+)
+
+func newPeopleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "people",
+		Aliases: []string{"person"},
+		Short:   "See how the people you eat with rate things",
+	}
+	cmd.AddCommand(
+		newPeopleCompareCmd(),
+	)
+	return cmd
+}
+
+func newPeopleCompareCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "compare",
+		Short: "Show who agrees with whom, and where each person strays from the group",
+		RunE:  runPeopleCompare,
+	}
+	bindFilter(cmd)
+	return cmd
+}
+
+func runPeopleCompare(cmd *cobra.Command, args []string) error {
+	f, err := newEntryFilterWithCmd(cmd)
+	if err != nil {
+		return err
+	}
+	diary := letseat.New(
+		letseat.WithFilter(*f),
+		letseat.WithDBFilename(mustGetCmd[string](*cmd, "data")),
+	)
+	defer dclose(diary)
+	entries := diary.Entries()
+	return g.Print(entries.Agreement())
+}
@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	letseat "github.com/drewstinnett/letseat/pkg"
+	"github.com/spf13/cobra"
+)
+
+func newPlaceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "place",
+		Aliases: []string{"places", "p"},
+		Short:   "Manage the places you eat at",
+	}
+	cmd.AddCommand(
+		newPlaceListCmd(),
+		newPlaceShowCmd(),
+		newPlaceSetCmd(),
+		newPlaceCloseCmd(),
+		newPlaceReopenCmd(),
+	)
+	return cmd
+}
+
+func newPlaceListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List all the known places",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			diary := letseat.New(
+				letseat.WithDBFilename(mustGetCmd[string](*cmd, "data")),
+			)
+			defer dclose(diary)
+			places, err := diary.Places()
+			if err != nil {
+				return err
+			}
+			names, err := getTierNames()
+			if err != nil {
+				return err
+			}
+			return g.Print(newPlaceListings(places, names))
+		},
+	}
+}
+
+// placeListing is the short version of a place, for listing a bunch of them
+type placeListing struct {
+	Name   string `yaml:"name" json:"name"`
+	Brand  string `yaml:"brand,omitempty" json:"brand,omitempty"`
+	Tier   string `yaml:"tier,omitempty" json:"tier,omitempty"`
+	Price  string `yaml:"price,omitempty" json:"price,omitempty"`
+	Status string `yaml:"status" json:"status"`
+}
+
+func newPlaceListings(places letseat.Places, names letseat.TierNames) []placeListing {
+	ret := make([]placeListing, len(places))
+	for idx, p := range places {
+		ret[idx] = placeListing{
+			Name:   p.Name,
+			Brand:  p.Brand,
+			Tier:   names.Name(p.Tier),
+			Status: p.Status.String(),
+		}
+		if p.Price != 0 {
+			ret[idx].Price = p.Price.String()
+		}
+	}
+	return ret
+}
+
+// placeReport is a place, along with the things we've figured out about it
+type placeReport struct {
+	letseat.Place `yaml:",inline"`
+	TierName      string `yaml:"tier_name,omitempty" json:"tier_name,omitempty"`
+	FirstVisit    string `yaml:"first_visit,omitempty" json:"first_visit,omitempty"`
+	Distance      string `yaml:"distance,omitempty" json:"distance,omitempty"`
+	Spend         string `yaml:"spend,omitempty" json:"spend,omitempty"`
+	PriceCheck    string `yaml:"price_check,omitempty" json:"price_check,omitempty"`
+	Trend         string `yaml:"trend,omitempty" json:"trend,omitempty"`
+}
+
+func newPlaceShowCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show PLACE",
+		Short: "Show everything we know about a place",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runPlaceShow,
+	}
+	cmd.Flags().String("from", "home", "Origin to measure the distance from, as defined in the config file")
+	return cmd
+}
+
+func runPlaceShow(cmd *cobra.Command, args []string) error {
+	diary := letseat.New(
+		letseat.WithDBFilename(mustGetCmd[string](*cmd, "data")),
+	)
+	defer dclose(diary)
+	place, err := diary.GetPlace(args[0])
+	if err != nil {
+		return err
+	}
+	names, err := getTierNames()
+	if err != nil {
+		return err
+	}
+	report := placeReport{Place: *place, TierName: names.Name(place.Tier)}
+	detail, err := diary.PlaceDetail(place.Name)
+	if err != nil {
+		return err
+	}
+	if detail.MedianCost != nil {
+		report.Spend = fmt.Sprintf("%v per person (median), %v", detail.MedianCost, detail.ActualPrice)
+		report.PriceCheck = detail.PriceComparison()
+	}
+	if detail.FirstVisit != nil {
+		report.FirstVisit = detail.FirstVisit.Format("2006-01-02")
+	}
+	if detail.Trend != nil {
+		report.Trend = detail.Trend.String()
+	}
+	from := mustGetCmd[string](*cmd, "from")
+	if origin, err := getOrigin(from); err == nil {
+		if d, ok := place.DistanceFrom(*origin); ok {
+			report.Distance = fmt.Sprintf("%v from %v", d, from)
+		}
+	} else if cmd.Flags().Changed("from") {
+		return err
+	}
+	return g.Print(report)
+}
+
+func newPlaceSetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set PLACE",
+		Short: "Set details on a place, adding it if it doesn't exist yet",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runPlaceSet,
+	}
+	cmd.Flags().StringSlice("modes", []string{}, "Service modes the place supports (dine-in, takeout, delivery, drive-thru, food-truck)")
+	cmd.Flags().String("address", "", "Street address")
+	cmd.Flags().String("neighborhood", "", "Neighborhood the place is in")
+	cmd.Flags().String("location", "", "Latitude and longitude, like 42.2808,-83.7430")
+	cmd.Flags().String("hours", "", "Weekly opening hours, like \"mon-fri 11:00-14:00,17:00-21:00; sat 10:00-22:00; sun closed\"")
+	cmd.Flags().StringSlice("closed-on", []string{}, "Dates the place is closed, like holidays, in the format YYYY-MM-DD")
+	cmd.Flags().String("tier", "", "Tier the place is in, like everyday or treat, as defined in the config file")
+	cmd.Flags().String("price", "", "Price level, like $$ or 2. Use 'auto' to guess it from what you've spent")
+	cmd.Flags().String("brand", "", "Brand the place is a location of, like a chain restaurant")
+	cmd.Flags().String("season", "", "Months a seasonal place is open, like may-sep")
+	return cmd
+}
+
+func runPlaceSet(cmd *cobra.Command, args []string) error {
+	diary := letseat.New(
+		letseat.WithDBFilename(mustGetCmd[string](*cmd, "data")),
+	)
+	defer dclose(diary)
+	place, err := diary.GetPlace(args[0])
+	if err != nil {
+		if place, err = letseat.NewPlace(letseat.WithName(args[0])); err != nil {
+			return err
+		}
+	}
+	if cmd.Flags().Changed("modes") {
+		modes, err := letseat.ParseModes(mustGetCmd[[]string](*cmd, "modes"))
+		if err != nil {
+			return err
+		}
+		place.Format = letseat.FormatWithModes(modes...)
+	}
+	if cmd.Flags().Changed("address") {
+		place.Address = mustGetCmd[string](*cmd, "address")
+	}
+	if cmd.Flags().Changed("neighborhood") {
+		place.Neighborhood = mustGetCmd[string](*cmd, "neighborhood")
+	}
+	if cmd.Flags().Changed("location") {
+		if place.Location, err = letseat.ParseCoordinates(mustGetCmd[string](*cmd, "location")); err != nil {
+			return err
+		}
+	}
+	if cmd.Flags().Changed("tier") {
+		names, err := getTierNames()
+		if err != nil {
+			return err
+		}
+		if place.Tier, err = names.Parse(mustGetCmd[string](*cmd, "tier")); err != nil {
+			return err
+		}
+	}
+	if cmd.Flags().Changed("price") {
+		if place.Price, err = placePrice(diary, place.Name, mustGetCmd[string](*cmd, "price")); err != nil {
+			return err
+		}
+	}
+	if cmd.Flags().Changed("brand") {
+		place.Brand = mustGetCmd[string](*cmd, "brand")
+	}
+	if cmd.Flags().Changed("season") {
+		if place.Season, err = letseat.ParseMonths(mustGetCmd[string](*cmd, "season")); err != nil {
+			return err
+		}
+		place.Status = letseat.StatusSeasonal
+	}
+	if err := setPlaceHours(cmd, place); err != nil {
+		return err
+	}
+	if err := diary.SavePlace(*place); err != nil {
+		return err
+	}
+	return g.Print(place)
+}
+
+// placePrice parses a price level, or suggests one from the median spend when given "auto"
+func placePrice(diary *letseat.Diary, name, s string) (letseat.PriceLevel, error) {
+	if s != "auto" {
+		return letseat.ParsePriceLevel(s)
+	}
+	detail, err := diary.PlaceDetail(name)
+	if err != nil {
+		return 0, err
+	}
+	if detail.ActualPrice == 0 {
+		return 0, fmt.Errorf("no costs logged for %v, so there's no way to guess the price", name)
+	}
+	return detail.ActualPrice, nil
+}
+
+func setPlaceHours(cmd *cobra.Command, place *letseat.Place) error {
+	if !cmd.Flags().Changed("hours") && !cmd.Flags().Changed("closed-on") {
+		return nil
+	}
+	if place.Hours == nil {
+		place.Hours = &letseat.Hours{}
+	}
+	if cmd.Flags().Changed("hours") {
+		weekly, err := letseat.ParseWeeklyHours(mustGetCmd[string](*cmd, "hours"))
+		if err != nil {
+			return err
+		}
+		place.Hours.Weekly = weekly
+	}
+	for _, ds := range mustGetCmd[[]string](*cmd, "closed-on") {
+		d, err := time.Parse("2006-01-02", ds)
+		if err != nil {
+			return err
+		}
+		place.Hours.Exceptions = append(place.Hours.Exceptions, letseat.HoursException{Date: d, Closed: true})
+	}
+	return nil
+}
+
+func newPlaceCloseCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "close PLACE",
+		Short: "Mark a place as closed, so it stops showing up in recommendations",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return updatePlaceStatus(cmd, args[0], func(p *letseat.Place) {
+				p.Close(mustGetCmd[bool](*cmd, "temporary"))
+			})
+		},
+	}
+	cmd.Flags().Bool("temporary", false, "The place is only closed for now, like for renovations")
+	return cmd
+}
+
+func newPlaceReopenCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "reopen PLACE",
+		Short: "Mark a closed place as open again",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return updatePlaceStatus(cmd, args[0], func(p *letseat.Place) {
+				p.Reopen()
+			})
+		},
+	}
+}
+
+func updatePlaceStatus(cmd *cobra.Command, name string, update func(*letseat.Place)) error {
+	diary := letseat.New(
+		letseat.WithDBFilename(mustGetCmd[string](*cmd, "data")),
+	)
+	defer dclose(diary)
+	place, err := diary.GetPlace(name)
+	if err != nil {
+		return err
+	}
+	update(place)
+	if err := diary.SavePlace(*place); err != nil {
+		return err
+	}
+	return g.Print(place)
+}
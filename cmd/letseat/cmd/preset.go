@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// presetAliases lets presets use the names that read naturally in a config
+// file, like "weekdays", for flags like --weekday
+var presetAliases = map[string]string{
+	"modes":          "mode",
+	"meals":          "meal",
+	"places":         "place",
+	"exclude-places": "exclude-place",
+	"weekdays":       "weekday",
+}
+
+// preset is a named set of flags from the config file
+type preset map[string]any
+
+// getPresets returns all the presets from the config file
+func getPresets() (map[string]preset, error) {
+	ret := map[string]preset{}
+	if err := viper.UnmarshalKey("presets", &ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// getPreset returns a single preset from the config file
+func getPreset(name string) (preset, error) {
+	presets, err := getPresets()
+	if err != nil {
+		return nil, err
+	}
+	p, ok := presets[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown preset: %v, add it to the presets in your config file", name)
+	}
+	return p, nil
+}
+
+// flags returns the preset as flag names and the values to set them to
+func (p preset) flags() map[string]string {
+	ret := make(map[string]string, len(p))
+	for k, v := range p {
+		name := strings.ReplaceAll(strings.ToLower(k), "_", "-")
+		if alias, ok := presetAliases[name]; ok {
+			name = alias
+		}
+		ret[name] = presetValue(v)
+	}
+	return ret
+}
+
+func presetValue(v any) string {
+	switch tv := v.(type) {
+	case []any:
+		items := make([]string, len(tv))
+		for idx, item := range tv {
+			items[idx] = fmt.Sprint(item)
+		}
+		return strings.Join(items, ",")
+	case []string:
+		return strings.Join(tv, ",")
+	}
+	return fmt.Sprint(v)
+}
+
+// applyPreset sets the flags from the --preset given, leaving alone any flags
+// that were set on the command line
+func applyPreset(cmd *cobra.Command) error {
+	name := mustGetCmd[string](*cmd, "preset")
+	if name == "" {
+		return nil
+	}
+	p, err := getPreset(name)
+	if err != nil {
+		return err
+	}
+	return setPresetFlags(cmd.Flags(), name, p)
+}
+
+func setPresetFlags(flags *pflag.FlagSet, name string, p preset) error {
+	for flag, value := range p.flags() {
+		f := flags.Lookup(flag)
+		if f == nil || flag == "preset" {
+			return fmt.Errorf("unknown setting in preset %v: %v", name, flag)
+		}
+		if f.Changed {
+			continue
+		}
+		if err := flags.Set(flag, value); err != nil {
+			return fmt.Errorf("invalid %v in preset %v: %w", flag, name, err)
+		}
+	}
+	return nil
+}
+
+func newPresetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "preset",
+		Aliases: []string{"presets"},
+		Short:   "Inspect the filter presets from the config file",
+	}
+	cmd.AddCommand(
+		newPresetListCmd(),
+		newPresetShowCmd(),
+	)
+	return cmd
+}
+
+func newPresetListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the presets",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			presets, err := getPresets()
+			if err != nil {
+				return err
+			}
+			ret := make([]presetReport, 0, len(presets))
+			for name, p := range presets {
+				ret = append(ret, presetReport{Name: name, Flags: p.flags()})
+			}
+			sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+			return g.Print(ret)
+		},
+	}
+}
+
+// presetReport is a preset, along with what it ends up filtering on
+type presetReport struct {
+	Name     string            `yaml:"name" json:"name"`
+	Flags    map[string]string `yaml:"flags" json:"flags"`
+	Earliest string            `yaml:"earliest,omitempty" json:"earliest,omitempty"`
+	Latest   string            `yaml:"latest,omitempty" json:"latest,omitempty"`
+}
+
+func newPresetShowCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show PRESET",
+		Short: "Show the filter a preset resolves to, along with any other filter flags given",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := cmd.Flags().Set("preset", args[0]); err != nil {
+				return err
+			}
+			if err := applyPreset(cmd); err != nil {
+				return err
+			}
+			f, err := newEntryFilterWithCmd(cmd)
+			if err != nil {
+				return err
+			}
+			report := presetReport{Name: args[0], Flags: map[string]string{}}
+			cmd.Flags().Visit(func(flag *pflag.Flag) {
+				if flag.Name != "preset" {
+					report.Flags[flag.Name] = strings.Trim(flag.Value.String(), "[]")
+				}
+			})
+			if f.Earliest != nil {
+				report.Earliest = f.Earliest.Format("2006-01-02 15:04")
+			}
+			if f.Latest != nil {
+				report.Latest = f.Latest.Format("2006-01-02 15:04")
+			}
+			return g.Print(report)
+		},
+	}
+	bindFilter(cmd)
+	return cmd
+}
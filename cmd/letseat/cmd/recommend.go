@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"sort"
 	"strings"
-	"time"
 
 	"github.com/charmbracelet/glamour"
 	letseat "github.com/drewstinnett/letseat/pkg"
@@ -20,26 +19,129 @@ func newRecommendCommand() *cobra.Command {
 		RunE:    runRecommend,
 	}
 	bindFilter(cmd)
+	bindPlaceFilter(cmd)
+	bindGrouping(cmd)
+	bindNormalize(cmd)
 	cmd.PersistentFlags().Int("top", 3, "return N number recommendations")
+	cmd.Flags().String("strategy", "time", "How to pick places: time (longest since the last visit) or overdue (favorites that are late compared to how often you usually go)")
 	return cmd
 }
 
+func bindPlaceFilter(cmd *cobra.Command) {
+	cmd.Flags().String("within", "", "Only include places within this distance, like 5km or 3mi")
+	cmd.Flags().String("from", "home", "Origin to measure distances from, as defined in the config file")
+	cmd.Flags().String("at", "", "Only include places open at this time, like \"fri 19:00\" (defaults to the current time)")
+	cmd.Flags().Bool("any-time", false, "Include places no matter when they are open")
+	cmd.Flags().StringSlice("tier", []string{}, "Only include places in these tiers, like everyday or treat")
+	cmd.Flags().String("occasion", "", "Only include places in the tiers that suit an occasion, like date-night")
+}
+
+func newPlaceFilterWithCmd(cmd *cobra.Command) (*letseat.PlaceFilter, error) {
+	f := &letseat.PlaceFilter{}
+	if within := mustGetCmd[string](*cmd, "within"); within != "" {
+		d, err := letseat.ParseDistance(within)
+		if err != nil {
+			return nil, err
+		}
+		if f.Origin, err = getOrigin(mustGetCmd[string](*cmd, "from")); err != nil {
+			return nil, err
+		}
+		f.Within = d
+	}
+	at := getCurrentDate(cmd)
+	timeKnown := hasCurrentTime(cmd)
+	if s := mustGetCmd[string](*cmd, "at"); s != "" {
+		timeKnown = true
+		var err error
+		if at, err = letseat.ResolveAt(s, at); err != nil {
+			return nil, err
+		}
+	}
+	f.ActiveOn = &at
+	tiers, err := newTiersWithCmd(cmd)
+	if err != nil {
+		return nil, err
+	}
+	f.Tiers = tiers
+	// Without a time of day there's no telling what's open, so only check the date
+	if timeKnown && !mustGetCmd[bool](*cmd, "any-time") {
+		f.OpenAt = &at
+	}
+	return f, nil
+}
+
+// newTiersWithCmd returns the tiers asked for with --tier and --occasion
+func newTiersWithCmd(cmd *cobra.Command) ([]int, error) {
+	names, err := getTierNames()
+	if err != nil {
+		return nil, err
+	}
+	tiers, err := names.ParseMany(mustGetCmd[[]string](*cmd, "tier"))
+	if err != nil {
+		return nil, err
+	}
+	if occasion := mustGetCmd[string](*cmd, "occasion"); occasion != "" {
+		occasions, err := getOccasions()
+		if err != nil {
+			return nil, err
+		}
+		got, err := occasions.Tiers(occasion, names)
+		if err != nil {
+			return nil, err
+		}
+		tiers = append(tiers, got...)
+	}
+	return tiers, nil
+}
+
 func runRecommend(cmd *cobra.Command, args []string) error {
+	f, err := newEntryFilterWithCmd(cmd)
+	if err != nil {
+		return err
+	}
+	n, err := newNormalizationWithCmd(cmd)
+	if err != nil {
+		return err
+	}
 	diary := letseat.New(
-		letseat.WithFilter(*mustNewEntryFilterWithCmd(cmd)),
+		letseat.WithFilter(*f),
+		letseat.WithNormalization(n),
 		letseat.WithDBFilename(mustGetCmd[string](*cmd, "data")),
 	)
+	pf, err := newPlaceFilterWithCmd(cmd)
+	if err != nil {
+		return err
+	}
+	by, err := newGroupingWithCmd(cmd)
+	if err != nil {
+		return err
+	}
 	topN := mustGetCmd[int](*cmd, "top")
-	placesDetails := diary.PlaceDetails()
-	sort.Slice(placesDetails, func(i, j int) bool {
-		return placesDetails[i].LastVisit.Before(*placesDetails[j].LastVisit)
-	})
+	now := getCurrentDate(cmd)
+	placesDetails := diary.PlaceDetailsBy(by).Filter(*pf)
 
-	lvisited := placesDetails[0:min(topN, len(placesDetails))]
 	doc := strings.Builder{}
-	doc.WriteString("# Recommendations Based on Time\n")
-	for _, item := range lvisited {
-		doc.WriteString(fmt.Sprintf("* %v (%v days ago)\n", item.Name, int(time.Since(*item.LastVisit).Hours()/24)))
+	switch strategy := mustGetCmd[string](*cmd, "strategy"); strategy {
+	case "time":
+		sort.Slice(placesDetails, func(i, j int) bool {
+			return placesDetails[i].LastVisit.Before(*placesDetails[j].LastVisit)
+		})
+		lvisited := placesDetails[0:min(topN, len(placesDetails))]
+		doc.WriteString("# Recommendations Based on Time\n")
+		for _, item := range lvisited {
+			doc.WriteString(fmt.Sprintf("* %v (%v days ago)\n", item.Name, item.DaysSince(now)))
+		}
+	case "overdue":
+		overdue := diary.OverdueBy(by, now).Filter(*pf)
+		doc.WriteString("# Overdue Favorites\n")
+		if len(overdue) == 0 {
+			doc.WriteString("Nothing is overdue, you're keeping up with all your favorites\n")
+		}
+		for _, item := range overdue[0:min(topN, len(overdue))] {
+			doc.WriteString(fmt.Sprintf("* %v (%v)\n", item.Name, overdueDetail(item, now)))
+		}
+	default:
+		return fmt.Errorf("unknown strategy: %v, must be one of time or overdue", strategy)
 	}
 	doc.WriteString("\n")
 
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlaceFilterOpenAt(t *testing.T) {
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		bindPlaceFilter(cmd)
+		cmd.Flags().String("current-date", "", "")
+		require.NoError(t, cmd.ParseFlags(args))
+		return cmd
+	}
+	f, err := newPlaceFilterWithCmd(newCmd("--current-date", "2024-03-13"))
+	require.NoError(t, err)
+	require.Nil(t, f.OpenAt, "a date alone doesn't say what's open")
+	require.Equal(t, "2024-03-13", f.ActiveOn.Format("2006-01-02"))
+
+	f, err = newPlaceFilterWithCmd(newCmd("--current-date", "2024-03-13 19:00"))
+	require.NoError(t, err)
+	require.Equal(t, "2024-03-13 19:00", f.OpenAt.Format("2006-01-02 15:04"))
+
+	f, err = newPlaceFilterWithCmd(newCmd("--current-date", "2024-03-13", "--at", "fri 12:00"))
+	require.NoError(t, err)
+	require.Equal(t, "2024-03-15 12:00", f.OpenAt.Format("2006-01-02 15:04"))
+
+	f, err = newPlaceFilterWithCmd(newCmd("--current-date", "2024-03-13 19:00", "--any-time"))
+	require.NoError(t, err)
+	require.Nil(t, f.OpenAt)
+}
@@ -4,6 +4,7 @@ import (
 	"log/slog"
 	"os"
 	"path"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/log"
@@ -12,6 +13,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/drewstinnett/go-output-format/v2/gout"
+	letseat "github.com/drewstinnett/letseat/pkg"
 	"github.com/spf13/viper"
 )
 
@@ -24,10 +26,11 @@ var (
 )
 
 type configPaths struct {
-	ConfigPath string
-	ConfigFile string
-	DataPath   string
-	DataFile   string
+	ConfigPath     string
+	ConfigFile     string
+	DataPath       string
+	DataFile       string
+	AttachmentPath string
 }
 
 // rootCmd represents the base command when called without any subcommands
@@ -57,6 +60,17 @@ func newRootCmd() *cobra.Command {
 		newImportCmd(),
 		newExportCmd(),
 		newEditCmd(),
+		newPlaceCmd(),
+		newAttachCmd(),
+		newGCCmd(),
+		newListCmd(),
+		newCostCmd(),
+		newPeopleCmd(),
+		newHeatmapCmd(),
+		newWrappedCmd(),
+		newPatternsCmd(),
+		newExploreCmd(),
+		newPresetCmd(),
 	)
 
 	return cmd
@@ -80,9 +94,10 @@ func init() {
 		}
 	}
 	config = configPaths{
-		ConfigPath: path.Join(xdg.ConfigHome, "letseat"),
-		DataPath:   data,
-		DataFile:   path.Join(data, "data.db"),
+		ConfigPath:     path.Join(xdg.ConfigHome, "letseat"),
+		DataPath:       data,
+		DataFile:       path.Join(data, "data.db"),
+		AttachmentPath: path.Join(data, "attachments"),
 	}
 	cobra.OnInitialize(initConfig)
 }
@@ -91,7 +106,7 @@ func bindRootArgs(cmd *cobra.Command) {
 	// cmd.PersistentFlags().StringP("diary", "d", config.DataFile, "diary file")
 	cmd.PersistentFlags().StringP("data", "d", config.DataFile, "Database containing all entries")
 	cmd.PersistentFlags().StringP("format", "f", "yaml", "Format of the output")
-	cmd.PersistentFlags().String("current-date", "", "Assume this as the current date, in the format YYYY-MM-DD")
+	cmd.PersistentFlags().String("current-date", "", "Assume this as the current date, in the format YYYY-MM-DD or YYYY-MM-DD HH:MM")
 }
 
 func getCurrentDate(cmd *cobra.Command) time.Time {
@@ -102,13 +117,30 @@ func getCurrentDate(cmd *cobra.Command) time.Time {
 	if ds == "" {
 		return time.Now()
 	}
-	t, err := time.Parse("2006-01-02", ds)
+	t, err := time.ParseInLocation("2006-01-02 15:04", ds, time.Local)
+	if err != nil {
+		t, err = time.ParseInLocation("2006-01-02", ds, time.Local)
+	}
 	if err != nil {
 		panic(err)
 	}
 	return t
 }
 
+// hasCurrentTime returns true if the current date comes with a time of day. A
+// --current-date without one only says which day it is
+func hasCurrentTime(cmd *cobra.Command) bool {
+	ds, err := cmd.Flags().GetString("current-date")
+	if err != nil {
+		panic(err)
+	}
+	if ds == "" {
+		return true
+	}
+	_, err = time.ParseInLocation("2006-01-02 15:04", ds, time.Local)
+	return err == nil
+}
+
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
 	// Find home directory.
@@ -134,4 +166,7 @@ func initConfig() {
 		config.ConfigFile = viper.ConfigFileUsed()
 		slog.Debug("using config file", "file", config.ConfigFile)
 	}
+	if currency := viper.GetString("currency"); currency != "" {
+		letseat.DefaultCurrency = strings.ToUpper(currency)
+	}
 }
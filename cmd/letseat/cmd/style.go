@@ -26,9 +26,11 @@ var (
 			MarginRight(2).
 			Render
 
-	ratingRow  = lipgloss.NewStyle().Width(50)
+	// Rows aren't wrapped, so the rating notes stay on one line
+	ratingRow  = lipgloss.NewStyle()
 	ratingKey  = lipgloss.NewStyle().AlignHorizontal(lipgloss.Right).Width(20).PaddingRight(2)
 	ratingItem = lipgloss.NewStyle().AlignHorizontal(lipgloss.Right).Width(20)
+	ratingNote = lipgloss.NewStyle().PaddingLeft(2).Foreground(lipgloss.Color("#626262"))
 
 	listItem      = lipgloss.NewStyle().PaddingLeft(2).Render
 	listItemMajor = lipgloss.NewStyle().PaddingLeft(2).Bold(true).Render
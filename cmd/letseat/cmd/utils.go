@@ -7,7 +7,6 @@ import (
 	"log/slog"
 	"os"
 	"reflect"
-	"strconv"
 	"time"
 
 	letseat "github.com/drewstinnett/letseat/pkg"
@@ -15,12 +14,16 @@ import (
 )
 
 // mustGetCmd uses generics to get a given flag with the appropriate Type from a cobra.Command
-func mustGetCmd[T []int | []string | int | string | bool | time.Duration](cmd cobra.Command, s string) T {
+func mustGetCmd[T []int | []string | int | float64 | string | bool | time.Duration](cmd cobra.Command, s string) T {
 	switch any(new(T)).(type) {
 	case *int:
 		item, err := cmd.Flags().GetInt(s)
 		panicIfErr(err)
 		return any(item).(T)
+	case *float64:
+		item, err := cmd.Flags().GetFloat64(s)
+		panicIfErr(err)
+		return any(item).(T)
 	case *string:
 		item, err := cmd.Flags().GetString(s)
 		panicIfErr(err)
@@ -61,15 +64,134 @@ func mustNewEntryFilterWithCmd(cmd *cobra.Command) *letseat.EntryFilter {
 }
 
 func newEntryFilterWithCmd(cmd *cobra.Command) (*letseat.EntryFilter, error) {
-	earliestD, err := letseat.ParseDuration(mustGetCmd[string](*cmd, "earliest"))
+	earliest, latest, err := dateFlags(cmd)
+	if err != nil {
+		return nil, err
+	}
+	modes, err := letseat.ParseModes(mustGetCmd[[]string](*cmd, "mode"))
+	if err != nil {
+		return nil, err
+	}
+	if mustGetCmd[bool](*cmd, "only-takeout") {
+		modes = append(modes, letseat.ModeTakeout)
+	}
+	if mustGetCmd[bool](*cmd, "only-dinein") {
+		modes = append(modes, letseat.ModeDineIn)
+	}
+	meals, err := letseat.ParseMealTypes(mustGetCmd[[]string](*cmd, "meal"))
+	if err != nil {
+		return nil, err
+	}
+	var maxPrice letseat.PriceLevel
+	if s := mustGetCmd[string](*cmd, "max-price"); s != "" {
+		if maxPrice, err = letseat.ParsePriceLevel(s); err != nil {
+			return nil, err
+		}
+	}
+	weekdays, err := letseat.ParseWeekdays(mustGetCmd[[]string](*cmd, "weekday")...)
+	if err != nil {
+		return nil, err
+	}
+	f := &letseat.EntryFilter{
+		Modes:         modes,
+		Meals:         meals,
+		Weekdays:      weekdays,
+		Places:        mustGetCmd[[]string](*cmd, "place"),
+		ExcludePlaces: mustGetCmd[[]string](*cmd, "exclude-place"),
+		With:          mustGetCmd[[]string](*cmd, "with"),
+		Earliest:      earliest,
+		Latest:        latest,
+		MaxPrice:      maxPrice,
+	}
+	if cmd.Flags().Changed("min-rating") {
+		f.MinRating = toPTR(mustGetCmd[float64](*cmd, "min-rating"))
+	}
+	if cmd.Flags().Changed("max-rating") {
+		f.MaxRating = toPTR(mustGetCmd[float64](*cmd, "max-rating"))
+	}
+	if f.Where, err = whereFlag(cmd); err != nil {
+		return nil, err
+	}
+	if f.MinCost, err = costFlag(cmd, "min-cost"); err != nil {
+		return nil, err
+	}
+	if f.MaxCost, err = costFlag(cmd, "max-cost"); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// dateFlags works out the earliest and latest dates from their flags, relative
+// to the current date. Periods like "last month" given to --earliest also set
+// the latest date, unless --latest says otherwise. Anything else, like a date
+// or a duration, is just where to start
+func dateFlags(cmd *cobra.Command) (*time.Time, *time.Time, error) {
+	now := getCurrentDate(cmd)
+	var earliest, latest *time.Time
+	if s := mustGetCmd[string](*cmd, "earliest"); s != "" {
+		r, err := letseat.ParseDateRange(s, now)
+		if err != nil {
+			return nil, nil, err
+		}
+		if r.Start == nil {
+			return nil, nil, fmt.Errorf("earliest date needs a start: %v", s)
+		}
+		earliest = r.Start
+		if letseat.IsPeriod(s) {
+			latest = r.End
+		}
+	}
+	if s := mustGetCmd[string](*cmd, "latest"); s != "" {
+		r, err := letseat.ParseDateRange(s, now)
+		if err != nil {
+			return nil, nil, err
+		}
+		latest = r.End
+		if latest == nil {
+			latest = r.Start
+		}
+	}
+	if earliest != nil && latest != nil && latest.Before(*earliest) {
+		return nil, nil, fmt.Errorf("latest date (%v) is before the earliest date (%v)", latest.Format("2006-01-02"), earliest.Format("2006-01-02"))
+	}
+	return earliest, latest, nil
+}
+
+// defaultEarliest sets --earliest to def when no dates were given at all, for
+// reports that need a window of time to make sense. If only --latest was
+// given, the usual default for --earliest is dropped, so the report reaches
+// back from --latest instead of failing
+func defaultEarliest(cmd *cobra.Command, def string) {
+	switch {
+	case cmd.Flags().Changed("earliest"):
+		return
+	case cmd.Flags().Changed("latest"):
+		panicIfErr(cmd.Flags().Set("earliest", ""))
+	default:
+		panicIfErr(cmd.Flags().Set("earliest", def))
+	}
+}
+
+// whereFlag parses the --where expression, returning nil if it wasn't given
+func whereFlag(cmd *cobra.Command) (*letseat.Where, error) {
+	s := mustGetCmd[string](*cmd, "where")
+	if s == "" {
+		return nil, nil
+	}
+	return letseat.ParseWhere(s)
+}
+
+// costFlag reads money out of a flag, returning nil if it wasn't given
+func costFlag(cmd *cobra.Command, name string) (*letseat.Money, error) {
+	s := mustGetCmd[string](*cmd, name)
+	if s == "" {
+		return nil, nil
+	}
+	m, err := letseat.ParseMoney(s, letseat.DefaultCurrency)
 	if err != nil {
 		return nil, err
 	}
-	return &letseat.EntryFilter{
-		OnlyTakeout: mustGetCmd[bool](*cmd, "only-takeout"),
-		OnlyDineIn:  mustGetCmd[bool](*cmd, "only-dinein"),
-		Earliest:    toPTR(getCurrentDate(cmd).Add(-earliestD)),
-	}, nil
+	return &m, nil
 }
 
 func toPTR[V any](v V) *V {
@@ -83,8 +205,12 @@ func validateDate(s string) error {
 	return nil
 }
 
-func validateNumber(s string) error {
-	_, err := strconv.Atoi(s)
+// validateClock allows an empty time, or anything letseat.ParseClock understands
+func validateClock(s string) error {
+	if s == "" {
+		return nil
+	}
+	_, err := letseat.ParseClock(s)
 	return err
 }
 
@@ -112,3 +238,11 @@ func dclose(c io.Closer) {
 		slog.Error("error closing file")
 	}
 }
+
+// plural returns a count with its noun, like "1 visit" or "3 visits"
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%v %v", n, noun)
+	}
+	return fmt.Sprintf("%v %vs", n, noun)
+}
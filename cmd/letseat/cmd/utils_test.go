@@ -3,6 +3,8 @@ package cmd
 import (
 	"testing"
 
+	"github.com/spf13/cobra"
+
 	"github.com/stretchr/testify/require"
 )
 
@@ -15,3 +17,70 @@ func TestValidatePlace(t *testing.T) {
 	require.NoError(t, validatePlace("some place"))
 	require.EqualError(t, validatePlace(""), "place cannot be empty")
 }
+
+func TestDateFlags(t *testing.T) {
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		bindFilter(cmd)
+		cmd.Flags().String("current-date", "2024-03-13", "")
+		require.NoError(t, cmd.ParseFlags(args))
+		return cmd
+	}
+	earliest, latest, err := dateFlags(newCmd())
+	require.NoError(t, err)
+	require.Equal(t, "2023-12-14", earliest.Format("2006-01-02"))
+	require.Nil(t, latest)
+
+	earliest, latest, err = dateFlags(newCmd("--earliest", "last month"))
+	require.NoError(t, err)
+	require.Equal(t, "2024-02-01", earliest.Format("2006-01-02"))
+	require.Equal(t, "2024-02-29", latest.Format("2006-01-02"), "periods set both ends")
+
+	earliest, latest, err = dateFlags(newCmd("--earliest", "2023-06-01"))
+	require.NoError(t, err)
+	require.Equal(t, "2023-06-01 00:00", earliest.Format("2006-01-02 15:04"))
+	require.Nil(t, latest, "a date is only where to start")
+
+	_, latest, err = dateFlags(newCmd("--earliest", "last year", "--latest", "2023-06-30"))
+	require.NoError(t, err)
+	require.Equal(t, "2023-06-30 23:59", latest.Format("2006-01-02 15:04"), "--latest wins")
+
+	_, latest, err = dateFlags(newCmd("--latest", "30d"))
+	require.NoError(t, err)
+	require.Equal(t, "2024-02-12", latest.Format("2006-01-02"))
+
+	_, _, err = dateFlags(newCmd("--earliest", "until 2024-01-01"))
+	require.EqualError(t, err, "earliest date needs a start: until 2024-01-01")
+	_, _, err = dateFlags(newCmd("--earliest", "this year", "--latest", "2023-01-01"))
+	require.EqualError(t, err, "latest date (2023-01-01) is before the earliest date (2024-01-01)")
+}
+
+func TestDefaultEarliest(t *testing.T) {
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		bindFilter(cmd)
+		cmd.Flags().String("current-date", "2024-03-13", "")
+		require.NoError(t, cmd.ParseFlags(args))
+		defaultEarliest(cmd, "1y")
+		return cmd
+	}
+	earliest, latest, err := dateFlags(newCmd())
+	require.NoError(t, err)
+	require.Equal(t, "2023-03-13", earliest.Format("2006-01-02"))
+	require.Nil(t, latest)
+
+	earliest, _, err = dateFlags(newCmd("--earliest", "30d"))
+	require.NoError(t, err)
+	require.Equal(t, "2024-02-12", earliest.Format("2006-01-02"))
+
+	earliest, latest, err = dateFlags(newCmd("--latest", "2022-12-31"))
+	require.NoError(t, err)
+	require.Nil(t, earliest, "the report reaches back from --latest")
+	require.Equal(t, "2022-12-31", latest.Format("2006-01-02"))
+}
+
+func TestValidateClock(t *testing.T) {
+	require.NoError(t, validateClock(""))
+	require.NoError(t, validateClock("6:30 PM"))
+	require.EqualError(t, validateClock("dinner time"), "invalid time of day: dinner time")
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	letseat "github.com/drewstinnett/letseat/pkg"
+	"github.com/spf13/cobra"
+)
+
+func newWrappedCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wrapped",
+		Short: "Look back over a year of eating out",
+		RunE:  runWrapped,
+	}
+	cmd.Flags().Int("year", 0, "Year to look back on (defaults to the year of the current date)")
+	cmd.Flags().Bool("markdown", false, "Print plain Markdown, to save or share")
+	cmd.Flags().Bool("no-pager", false, "Print straight to the terminal instead of paging")
+	return cmd
+}
+
+func runWrapped(cmd *cobra.Command, args []string) error {
+	year := mustGetCmd[int](*cmd, "year")
+	if year == 0 {
+		year = getCurrentDate(cmd).Year()
+	}
+	diary := letseat.New(
+		letseat.WithDBFilename(mustGetCmd[string](*cmd, "data")),
+	)
+	defer dclose(diary)
+	entries := diary.Entries()
+	w := entries.Wrapped(year)
+	if w.Meals == 0 {
+		return fmt.Errorf("no entries found for %v", year)
+	}
+
+	md := wrappedMarkdown(w)
+	if mustGetCmd[bool](*cmd, "markdown") {
+		fmt.Fprint(cmd.OutOrStdout(), md)
+		return nil
+	}
+	out, err := getRenderer().Render(md)
+	if err != nil {
+		return err
+	}
+	if mustGetCmd[bool](*cmd, "no-pager") || !isTerminal(os.Stdout) {
+		fmt.Fprint(cmd.OutOrStdout(), out)
+		return nil
+	}
+	_, err = tea.NewProgram(newPager(fmt.Sprintf("%v Wrapped", year), out), tea.WithAltScreen()).Run()
+	return err
+}
+
+// wrappedMarkdown writes the year in review out as a Markdown document
+func wrappedMarkdown(w letseat.Wrapped) string {
+	doc := strings.Builder{}
+	fmt.Fprintf(&doc, "# %v Wrapped\n\n", w.Year)
+	fmt.Fprintf(&doc, "You ate out **%v** times", w.Meals)
+	if !w.Spent.IsZero() {
+		fmt.Fprintf(&doc, " and spent **%v**", w.Spent)
+	}
+	doc.WriteString(".\n\n")
+
+	if w.BusiestMonth != nil {
+		fmt.Fprintf(&doc, "Your busiest month was **%v**, with %v out.\n\n", w.BusiestMonth.Month, plural(w.BusiestMonth.Meals, "meal"))
+	}
+
+	doc.WriteString("## Most Visited\n\n")
+	for idx, p := range w.MostVisited {
+		fmt.Fprintf(&doc, "%v. %v (%v)\n", idx+1, p.Place, plural(p.Visits, "visit"))
+	}
+	doc.WriteString("\n")
+
+	if len(w.BestRated) > 0 {
+		doc.WriteString("## Best Rated\n\n")
+		for idx, p := range w.BestRated {
+			fmt.Fprintf(&doc, "%v. %v (%.1f★, from a %.1f★ average over %v)\n", idx+1, p.Place, p.Rating, p.Average, plural(p.Visits, "visit"))
+		}
+		doc.WriteString("\n")
+	}
+
+	if len(w.NewPlaces) > 0 {
+		fmt.Fprintf(&doc, "## New Places\n\nYou tried %v: %v.\n\n", plural(len(w.NewPlaces), "new place"), strings.Join(w.NewPlaces, ", "))
+	}
+
+	if len(w.Favorites) > 0 {
+		doc.WriteString("## Favorites\n\n")
+		people := make([]string, 0, len(w.Favorites))
+		for name := range w.Favorites {
+			people = append(people, name)
+		}
+		sort.Strings(people)
+		for _, name := range people {
+			fmt.Fprintf(&doc, "* **%v** loved %v\n", name, w.Favorites[name])
+		}
+		doc.WriteString("\n")
+	}
+
+	if w.LongestStreak != nil || w.PlaceStreak != nil {
+		doc.WriteString("## Streaks\n\n")
+		if s := w.LongestStreak; s != nil && s.Days > 1 {
+			fmt.Fprintf(&doc, "* %v days in a row eating out, from %v to %v\n", s.Days, s.Start.Format("Jan 2"), s.End.Format("Jan 2"))
+		}
+		if s := w.PlaceStreak; s != nil && s.Visits > 1 {
+			fmt.Fprintf(&doc, "* %v visits in a row to %v\n", s.Visits, s.Place)
+		}
+		doc.WriteString("\n")
+	}
+
+	if s := w.Surprise; s != nil {
+		how := "better"
+		if s.Rating < s.Expected {
+			how = "worse"
+		}
+		fmt.Fprintf(&doc, "## Biggest Surprise\n\n%v on %v was rated %.1f★, a lot %v than its usual %.1f★.\n",
+			s.Place, s.Date.Format("Jan 2"), s.Rating, how, s.Expected)
+	}
+	return doc.String()
+}
+
+// isTerminal returns true if f is an interactive terminal, and not a pipe or file
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice != 0
+}
+
+// pager scrolls through a long document
+type pager struct {
+	title    string
+	content  string
+	ready    bool
+	viewport viewport.Model
+}
+
+func newPager(title, content string) pager {
+	return pager{title: title, content: content}
+}
+
+// Init satisfies the bubble interface
+func (p pager) Init() tea.Cmd {
+	return nil
+}
+
+func (p pager) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return p, tea.Quit
+		}
+	case tea.WindowSizeMsg:
+		// Leave room for the title and help lines
+		height := msg.Height - 2
+		if !p.ready {
+			p.viewport = viewport.New(msg.Width, height)
+			p.viewport.SetContent(p.content)
+			p.ready = true
+		} else {
+			p.viewport.Width = msg.Width
+			p.viewport.Height = height
+		}
+	}
+	var cmd tea.Cmd
+	p.viewport, cmd = p.viewport.Update(msg)
+	return p, cmd
+}
+
+// View shows the visible part of the document
+func (p pager) View() string {
+	if !p.ready {
+		return ""
+	}
+	return titleStyle.Render(p.title) + "\n" +
+		p.viewport.View() + "\n" +
+		helpStyle(fmt.Sprintf("%3.f%%  ↑/↓ to scroll, q to quit", p.viewport.ScrollPercent()*100))
+}
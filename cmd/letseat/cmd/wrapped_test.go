@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	letseat "github.com/drewstinnett/letseat/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrappedMarkdown(t *testing.T) {
+	got := wrappedMarkdown(letseat.Wrapped{
+		Year:         2023,
+		Meals:        12,
+		Spent:        letseat.MustParseMoney("250", "USD"),
+		NewPlaces:    []string{"Mezcalito"},
+		MostVisited:  []letseat.PlaceCount{{Place: "Pizza Dude", Visits: 5}},
+		BestRated:    []letseat.PlaceRating{{Place: "Mezcalito", Rating: 4.4, Average: 5, Visits: 1}},
+		Favorites:    map[string]string{"james": "Pizza Dude", "drew": "Mezcalito"},
+		BusiestMonth: &letseat.MonthCount{Month: time.March, Meals: 4},
+		Surprise:     &letseat.RatedSurprise{Place: "Pizza Dude", Date: time.Date(2023, 7, 15, 0, 0, 0, 0, time.UTC), Rating: 2, Expected: 4.5},
+	})
+	require.Contains(t, got, "# 2023 Wrapped\n")
+	require.Contains(t, got, "You ate out **12** times and spent **$250.00**.")
+	require.Contains(t, got, "1. Pizza Dude (5 visits)")
+	require.Contains(t, got, "1. Mezcalito (4.4★, from a 5.0★ average over 1 visit)")
+	require.Contains(t, got, "You tried 1 new place: Mezcalito.")
+	require.Contains(t, got, "* **drew** loved Mezcalito\n* **james** loved Pizza Dude")
+	require.Contains(t, got, "Pizza Dude on Jul 15 was rated 2.0★, a lot worse than its usual 4.5★.")
+	require.NotContains(t, got, "## Streaks")
+}
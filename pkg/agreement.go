@@ -0,0 +1,159 @@
+package letseat
+
+import (
+	"math"
+	"slices"
+	"sort"
+
+	"github.com/montanaflynn/stats"
+)
+
+// RaterPair is how much two people agree on the meals they both rated
+type RaterPair struct {
+	A      string `yaml:"a"`
+	B      string `yaml:"b"`
+	Shared int    `yaml:"shared"`
+	// Correlation runs from -1 (opposite tastes) to 1 (same tastes). 0 if
+	// there are too few shared meals to tell
+	Correlation float64 `yaml:"correlation"`
+	// MeanDifference is how many stars apart they are on an average meal
+	MeanDifference float64 `yaml:"mean_difference"`
+}
+
+// Controversy is a place where someone's ratings stray from everyone else's
+type Controversy struct {
+	Place  string  `yaml:"place"`
+	Rating float64 `yaml:"rating"`
+	Group  float64 `yaml:"group"`
+	// Difference is the rating minus the group's, so negative means they liked it less
+	Difference float64 `yaml:"difference"`
+}
+
+// Agreement is who agrees with whom on the meals they shared
+type Agreement struct {
+	Pairs []RaterPair `yaml:"pairs"`
+	// Controversial are the places each person disagrees with the group on the most
+	Controversial map[string][]Controversy `yaml:"controversial"`
+	// Biases are how generous or harsh each person is
+	Biases []RaterBias `yaml:"biases"`
+}
+
+// MaxControversies is how many controversial places are kept for each person
+const MaxControversies = 3
+
+// Raters returns everyone who has rated a meal, sorted by name. A 0 is "No
+// Rating", so it doesn't count, here or anywhere else in the agreement
+func (e *Entries) Raters() []string {
+	ret := []string{}
+	for _, entry := range *e {
+		for name := range entry.rated() {
+			if !slices.Contains(ret, name) {
+				ret = append(ret, name)
+			}
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Agreement compares every pair of raters over the meals they both rated
+func (e *Entries) Agreement() Agreement {
+	raters := e.Raters()
+	ret := Agreement{Pairs: []RaterPair{}, Controversial: map[string][]Controversy{}, Biases: e.RaterBiases()}
+	for i, a := range raters {
+		for _, b := range raters[i+1:] {
+			ret.Pairs = append(ret.Pairs, e.raterPair(a, b))
+		}
+	}
+	sort.SliceStable(ret.Pairs, func(i, j int) bool {
+		return ret.Pairs[i].Correlation > ret.Pairs[j].Correlation
+	})
+	for _, name := range raters {
+		if c := e.controversies(name); len(c) > 0 {
+			ret.Controversial[name] = c
+		}
+	}
+	return ret
+}
+
+func (e *Entries) raterPair(a, b string) RaterPair {
+	ret := RaterPair{A: a, B: b}
+	as, bs := []float64{}, []float64{}
+	var diff float64
+	for _, entry := range *e {
+		ratings := entry.rated()
+		ra, aok := ratings[a]
+		rb, bok := ratings[b]
+		if !aok || !bok {
+			continue
+		}
+		as = append(as, float64(ra))
+		bs = append(bs, float64(rb))
+		diff += math.Abs(float64(ra - rb))
+	}
+	ret.Shared = len(as)
+	if ret.Shared == 0 {
+		return ret
+	}
+	ret.MeanDifference = round2(diff / float64(ret.Shared))
+	if ret.Shared > 2 {
+		if r, err := stats.Correlation(as, bs); err == nil {
+			ret.Correlation = round2(r)
+		}
+	}
+	return ret
+}
+
+// controversies returns the places where name strays the furthest from
+// everyone else who rated the same meals
+func (e *Entries) controversies(name string) []Controversy {
+	type tally struct {
+		own, group float64
+		n          int
+	}
+	byPlace := map[string]*tally{}
+	for _, entry := range *e {
+		ratings := entry.rated()
+		own, ok := ratings[name]
+		if !ok || len(ratings) < 2 {
+			continue
+		}
+		var others float64
+		for n, r := range ratings {
+			if n != name {
+				others += float64(r)
+			}
+		}
+		if _, ok := byPlace[entry.Place]; !ok {
+			byPlace[entry.Place] = &tally{}
+		}
+		t := byPlace[entry.Place]
+		t.own += float64(own)
+		t.group += others / float64(len(ratings)-1)
+		t.n++
+	}
+	ret := []Controversy{}
+	for place, t := range byPlace {
+		c := Controversy{
+			Place:      place,
+			Rating:     round2(t.own / float64(t.n)),
+			Group:      round2(t.group / float64(t.n)),
+			Difference: round2((t.own - t.group) / float64(t.n)),
+		}
+		if c.Difference != 0 {
+			ret = append(ret, c)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if math.Abs(ret[i].Difference) != math.Abs(ret[j].Difference) {
+			return math.Abs(ret[i].Difference) > math.Abs(ret[j].Difference)
+		}
+		return ret[i].Place < ret[j].Place
+	})
+	return ret[:min(len(ret), MaxControversies)]
+}
+
+// round2 rounds to 2 decimal places, so reports stay readable
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
@@ -0,0 +1,53 @@
+package letseat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgreement(t *testing.T) {
+	entries := Entries{
+		{Place: "Pizza Dude", Ratings: map[string]int{"drew": 5, "james": 4, "layla": 1}},
+		{Place: "Taco Tuesday", Ratings: map[string]int{"drew": 3, "james": 2, "layla": 4}},
+		{Place: "BBQ Papa", Ratings: map[string]int{"drew": 4, "james": 3, "layla": 3}},
+		{Place: "Franks Place", Ratings: map[string]int{"drew": 2}},
+	}
+	require.Equal(t, []string{"drew", "james", "layla"}, entries.Raters())
+
+	got := entries.Agreement()
+	require.Equal(t, []RaterPair{
+		{A: "drew", B: "james", Shared: 3, Correlation: 1, MeanDifference: 1},
+		{A: "drew", B: "layla", Shared: 3, Correlation: -0.98, MeanDifference: 2},
+		{A: "james", B: "layla", Shared: 3, Correlation: -0.98, MeanDifference: 1.67},
+	}, got.Pairs)
+	require.Equal(t, []Controversy{
+		{Place: "Pizza Dude", Rating: 1, Group: 4.5, Difference: -3.5},
+		{Place: "Taco Tuesday", Rating: 4, Group: 2.5, Difference: 1.5},
+		{Place: "BBQ Papa", Rating: 3, Group: 3.5, Difference: -0.5},
+	}, got.Controversial["layla"])
+	require.Equal(t, []Controversy{
+		{Place: "Pizza Dude", Rating: 5, Group: 2.5, Difference: 2.5},
+		{Place: "BBQ Papa", Rating: 4, Group: 3, Difference: 1},
+	}, got.Controversial["drew"], "agreeing on Taco Tuesday isn't controversial, and nobody else rated Franks Place")
+
+	alone := Entries{{Place: "Pizza Dude", Ratings: map[string]int{"drew": 5}}}
+	require.Equal(t, Agreement{
+		Pairs:         []RaterPair{},
+		Controversial: map[string][]Controversy{},
+		Biases:        []RaterBias{{Person: "drew", Ratings: 1, Mean: 5}},
+	}, alone.Agreement())
+}
+
+func TestAgreementSkipsNoRating(t *testing.T) {
+	// Older diaries gave everyone a 0 rating, which means "No Rating"
+	entries := Entries{
+		{Place: "Pizza Dude", Ratings: map[string]int{"drew": 5, "james": 0}},
+		{Place: "Taco Tuesday", Ratings: map[string]int{"drew": 4, "james": 4, "layla": 0}},
+	}
+	require.Equal(t, []string{"drew", "james"}, entries.Raters())
+
+	got := entries.Agreement()
+	require.Equal(t, []RaterPair{{A: "drew", B: "james", Shared: 1}}, got.Pairs)
+	require.Empty(t, got.Controversial)
+}
@@ -0,0 +1,214 @@
+package letseat
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// AttachmentKind is what an attachment is a picture of
+type AttachmentKind string
+
+const (
+	// AttachmentPhoto is a photo of the food, or the place
+	AttachmentPhoto AttachmentKind = "photo"
+	// AttachmentReceipt is a copy of the receipt
+	AttachmentReceipt AttachmentKind = "receipt"
+)
+
+// ParseAttachmentKind returns an AttachmentKind from a string
+func ParseAttachmentKind(s string) (AttachmentKind, error) {
+	switch k := AttachmentKind(s); k {
+	case AttachmentPhoto, AttachmentReceipt:
+		return k, nil
+	}
+	return "", fmt.Errorf("unknown attachment kind: %v", s)
+}
+
+// Attachment is a file, like a photo or a receipt, that goes along with an entry
+type Attachment struct {
+	Hash string         `yaml:"hash"`
+	Name string         `yaml:"name,omitempty"`
+	Kind AttachmentKind `yaml:"kind,omitempty"`
+}
+
+// AttachmentStore keeps attachment files on disk, named by the sha256 of their
+// contents so the same file is never stored twice
+type AttachmentStore struct {
+	dir string
+}
+
+// NewAttachmentStore returns a new AttachmentStore rooted in the given directory
+func NewAttachmentStore(dir string) *AttachmentStore {
+	return &AttachmentStore{dir: dir}
+}
+
+// Dir is the directory holding all the attachments
+func (s AttachmentStore) Dir() string {
+	return s.dir
+}
+
+// Path returns where an attachment lives on disk
+func (s AttachmentStore) Path(a Attachment) string {
+	return filepath.Join(s.dir, s.RelPath(a.Hash))
+}
+
+// RelPath returns where a given hash lives, relative to the store directory
+func (s AttachmentStore) RelPath(hash string) string {
+	if len(hash) < 2 {
+		return hash
+	}
+	return filepath.Join(hash[:2], hash)
+}
+
+// Add copies a file in to the store, returning the attachment pointing to it
+func (s AttachmentStore) Add(fn string, kind AttachmentKind) (a *Attachment, err error) {
+	f, err := os.Open(filepath.Clean(fn))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := f.Close(); cerr != nil {
+			a, err = nil, errors.Join(err, cerr)
+		}
+	}()
+
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp(s.dir, ".incoming-*")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, h), f); err != nil {
+		return nil, errors.Join(err, tmp.Close())
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+
+	a = &Attachment{
+		Hash: hex.EncodeToString(h.Sum(nil)),
+		Name: filepath.Base(fn),
+		Kind: kind,
+	}
+	target := s.Path(*a)
+	if _, err := os.Stat(target); err == nil {
+		return a, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o700); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Hashes returns the hashes of every file in the store
+func (s AttachmentStore) Hashes() ([]string, error) {
+	ret := []string{}
+	err := filepath.WalkDir(s.dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		rel, err := filepath.Rel(s.dir, p)
+		if err != nil {
+			return err
+		}
+		// Only files sitting in a hash prefix directory are attachments
+		if d.IsDir() || strings.Count(rel, string(filepath.Separator)) != 1 {
+			return nil
+		}
+		ret = append(ret, d.Name())
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(ret)
+	return ret, nil
+}
+
+// Orphans returns the hashes in the store that none of the entries refer to
+func (s AttachmentStore) Orphans(entries Entries) ([]string, error) {
+	hashes, err := s.Hashes()
+	if err != nil {
+		return nil, err
+	}
+	used := map[string]bool{}
+	for _, entry := range entries {
+		for _, a := range entry.Attachments {
+			used[a.Hash] = true
+		}
+	}
+	ret := []string{}
+	for _, h := range hashes {
+		if !used[h] {
+			ret = append(ret, h)
+		}
+	}
+	return ret, nil
+}
+
+// GC removes any files in the store that none of the entries refer to,
+// returning the hashes that were removed
+func (s AttachmentStore) GC(entries Entries) ([]string, error) {
+	orphans, err := s.Orphans(entries)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range orphans {
+		if err := os.Remove(filepath.Join(s.dir, s.RelPath(h))); err != nil {
+			return nil, err
+		}
+	}
+	return orphans, nil
+}
+
+// Attach adds an attachment to the entry with the given key
+func (d *Diary) Attach(key string, a Attachment) (*Entry, error) {
+	var e Entry
+	if err := d.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(EntriesBucket))
+		v := b.Get([]byte(key))
+		if v == nil {
+			return fmt.Errorf("record not found: %v", key)
+		}
+		if err := json.Unmarshal(v, &e); err != nil {
+			return err
+		}
+		if slices.ContainsFunc(e.Attachments, func(item Attachment) bool { return item.Hash == a.Hash }) {
+			return nil
+		}
+		e.Attachments = append(e.Attachments, a)
+		return b.Put([]byte(key), e.mustMarshal())
+	}); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// AllEntries returns every entry in the diary, ignoring the filter
+func (d Diary) AllEntries() (Entries, error) {
+	return d.allEntries()
+}
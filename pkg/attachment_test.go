@@ -0,0 +1,67 @@
+package letseat
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachmentStore(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "receipt.jpg")
+	require.NoError(t, os.WriteFile(src, []byte("not really a jpeg"), 0o600))
+
+	s := NewAttachmentStore(filepath.Join(t.TempDir(), "attachments"))
+	got, err := s.Add(src, AttachmentReceipt)
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		&Attachment{
+			Hash: "21ac2586e213d1f490778a07bf0025a98fc57595863a282372bac594b398322b",
+			Name: "receipt.jpg",
+			Kind: AttachmentReceipt,
+		},
+		got,
+	)
+	b, err := os.ReadFile(s.Path(*got))
+	require.NoError(t, err)
+	require.Equal(t, "not really a jpeg", string(b))
+
+	// Adding the same content again is a no-op
+	again, err := s.Add(src, AttachmentPhoto)
+	require.NoError(t, err)
+	require.Equal(t, got.Hash, again.Hash)
+
+	hashes, err := s.Hashes()
+	require.NoError(t, err)
+	require.Equal(t, []string{got.Hash}, hashes)
+
+	orphans, err := s.GC(Entries{{Place: "A", Attachments: []Attachment{*got}}})
+	require.NoError(t, err)
+	require.Empty(t, orphans)
+
+	orphans, err = s.GC(Entries{{Place: "A"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{got.Hash}, orphans)
+	require.NoFileExists(t, s.Path(*got))
+}
+
+func TestDiaryAttach(t *testing.T) {
+	d := New(WithDB(newTestDB(t)))
+	e := Entry{Place: "Mamacitas", Mode: ModeDineIn, Date: toPTR(time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC))}
+	require.NoError(t, d.Log(e))
+
+	a := Attachment{Hash: "abc123", Name: "tacos.png", Kind: AttachmentPhoto}
+	got, err := d.Attach(e.Key(), a)
+	require.NoError(t, err)
+	require.Equal(t, []Attachment{a}, got.Attachments)
+
+	got, err = d.Attach(e.Key(), a)
+	require.NoError(t, err)
+	require.Len(t, got.Attachments, 1, "attaching the same thing twice does nothing")
+
+	_, err = d.Attach("never-exists", a)
+	require.EqualError(t, err, "record not found: never-exists")
+}
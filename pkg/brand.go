@@ -0,0 +1,117 @@
+package letseat
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Grouping is how places are grouped together when analyzing them
+type Grouping string
+
+const (
+	// GroupByLocation treats every location as its own place
+	GroupByLocation Grouping = "location"
+	// GroupByBrand lumps all the locations of a brand together
+	GroupByBrand Grouping = "brand"
+)
+
+// ParseGrouping returns a Grouping from a string
+func ParseGrouping(s string) (Grouping, error) {
+	switch g := Grouping(strings.ToLower(strings.TrimSpace(s))); g {
+	case GroupByLocation, GroupByBrand:
+		return g, nil
+	}
+	return "", fmt.Errorf("unknown grouping: %v, must be one of brand or location", s)
+}
+
+// WithBrand sets the brand of a place using functional options
+func WithBrand(b string) func(*Place) {
+	return func(p *Place) {
+		p.Brand = b
+	}
+}
+
+// BrandName returns the brand a place belongs to. Places without a brand are
+// their own brand
+func (p Place) BrandName() string {
+	if p.Brand != "" {
+		return p.Brand
+	}
+	return p.Name
+}
+
+// BrandOf returns the brand of a place by name. Places we don't know about are
+// their own brand
+func (p Places) BrandOf(name string) string {
+	if place := p.Find(name); place != nil {
+		return place.BrandName()
+	}
+	return name
+}
+
+// Locations returns all the places belonging to a brand
+func (p Places) Locations(brand string) Places {
+	ret := Places{}
+	for _, place := range p {
+		if strings.EqualFold(place.BrandName(), brand) {
+			ret = append(ret, place)
+		}
+	}
+	return ret
+}
+
+// Brands returns the names of every brand in the registry
+func (p Places) Brands() []string {
+	ret := []string{}
+	for _, place := range p {
+		if b := place.BrandName(); !slices.Contains(ret, b) {
+			ret = append(ret, b)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// PlaceDetailsBy is like PlaceDetails, but groups the places together in the given way
+func (d Diary) PlaceDetailsBy(g Grouping) PlaceDetails {
+	return d.placeDetailsByOf(d.Entries(), g)
+}
+
+// placeDetailsByOf summarizes the given entries, grouped in the given way
+func (d Diary) placeDetailsByOf(e Entries, g Grouping) PlaceDetails {
+	if g != GroupByBrand {
+		return d.placeDetailsOf(e)
+	}
+	registry, err := d.Places()
+	if err != nil {
+		slog.Warn("error reading places", "error", err)
+	}
+	byBrand := map[string]Entries{}
+	brands := []string{}
+	for _, entry := range e {
+		b := registry.BrandOf(entry.Place)
+		if _, ok := byBrand[b]; !ok {
+			brands = append(brands, b)
+		}
+		byBrand[b] = append(byBrand[b], entry)
+	}
+	sort.Strings(brands)
+	ret := make(PlaceDetails, len(brands))
+	for idx, b := range brands {
+		entries := byBrand[b]
+		det := entries.summarize(b)
+		locations := registry.Locations(b)
+		for i := range locations {
+			det.Locations = append(det.Locations, &locations[i])
+		}
+		if len(det.Locations) == 1 {
+			det.Place = det.Locations[0]
+		}
+		ret[idx] = *det
+	}
+	ret.Score()
+	return ret
+}
@@ -0,0 +1,61 @@
+package letseat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGrouping(t *testing.T) {
+	got, err := ParseGrouping("Brand")
+	require.NoError(t, err)
+	require.Equal(t, GroupByBrand, got)
+	_, err = ParseGrouping("cuisine")
+	require.EqualError(t, err, "unknown grouping: cuisine, must be one of brand or location")
+}
+
+func TestPlacesBrands(t *testing.T) {
+	places := Places{
+		*MustNewPlace(WithName("Pizza Chain Main St"), WithBrand("Pizza Chain")),
+		*MustNewPlace(WithName("Pizza Chain Airport"), WithBrand("Pizza Chain")),
+		*MustNewPlace(WithName("Taco Tuesday")),
+	}
+	require.Equal(t, []string{"Pizza Chain", "Taco Tuesday"}, places.Brands())
+	require.Equal(t, "Pizza Chain", places.BrandOf("Pizza Chain Airport"))
+	require.Equal(t, "Mystery Spot", places.BrandOf("Mystery Spot"))
+	require.Len(t, places.Locations("pizza chain"), 2)
+}
+
+func TestPlaceDetailsBy(t *testing.T) {
+	db := newTestDB(t)
+	d := New(WithDB(db))
+	for _, item := range []Entry{
+		{Place: "Pizza Chain Main St", Date: toPTR(mustTime("2024-01-01 00:00")), Ratings: map[string]int{"drew": 5}},
+		{Place: "Pizza Chain Airport", Date: toPTR(mustTime("2024-02-01 00:00")), Ratings: map[string]int{"drew": 4}},
+		{Place: "Taco Tuesday", Date: toPTR(mustTime("2024-01-15 00:00")), Ratings: map[string]int{"drew": 3}},
+	} {
+		require.NoError(t, d.Log(item))
+	}
+	for _, name := range []string{"Pizza Chain Main St", "Pizza Chain Airport"} {
+		require.NoError(t, d.SavePlace(*MustNewPlace(WithName(name), WithBrand("Pizza Chain"))))
+	}
+	d = New(WithDB(db))
+
+	require.Len(t, d.PlaceDetailsBy(GroupByLocation), 3)
+
+	got := d.PlaceDetailsBy(GroupByBrand)
+	require.Len(t, got, 2)
+	require.Equal(t, "Pizza Chain", got[0].Name)
+	require.Equal(t, 2, got[0].Visits)
+	require.Equal(t, 4.5, got[0].AverageRating)
+	require.Equal(t, "2024-02-01", got[0].LastVisit.Format("2006-01-02"))
+	require.Len(t, got[0].Locations, 2)
+	require.Nil(t, got[0].Place, "brands with many locations aren't any one place")
+	require.False(t, got[0].IsClosed())
+
+	got[0].Locations[0].Close(false)
+	require.False(t, got[0].IsClosed(), "one location is still open")
+	got[0].Locations[1].Close(false)
+	require.True(t, got[0].IsClosed())
+	require.Empty(t, PlaceDetails{got[0]}.Filter(PlaceFilter{ActiveOn: got[0].LastVisit}))
+}
@@ -0,0 +1,95 @@
+package letseat
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/montanaflynn/stats"
+)
+
+const (
+	// MinCadenceVisits is how many visits a place needs before we guess how often it gets visited
+	MinCadenceVisits = 3
+	// OverdueRatio is how many of its usual gaps have to go by before a place is overdue
+	OverdueRatio = 1.5
+	// FavoriteRating is the lowest average rating that counts as a favorite
+	FavoriteRating = 4.0
+)
+
+const day = 24 * time.Hour
+
+// cadence returns the median time between visits, going by the local calendar
+// day. Returns 0 if there aren't enough visits to tell
+func (e *Entries) cadence() time.Duration {
+	days := []time.Time{}
+	for _, entry := range *e {
+		if entry.Date == nil {
+			continue
+		}
+		d := startOfDay(*entry.Date)
+		if !slices.ContainsFunc(days, d.Equal) {
+			days = append(days, d)
+		}
+	}
+	if len(days) < MinCadenceVisits {
+		return 0
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	gaps := make([]float64, len(days)-1)
+	for idx := range gaps {
+		gaps[idx] = days[idx+1].Sub(days[idx]).Hours()
+	}
+	m, err := stats.Median(gaps)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(m) * time.Hour
+}
+
+// CadenceDays returns the usual number of days between visits, or 0 if we don't know
+func (p PlaceDetail) CadenceDays() int {
+	return int((p.Cadence + day/2) / day)
+}
+
+// DaysSince returns how many days it's been since the last visit
+func (p PlaceDetail) DaysSince(now time.Time) int {
+	if p.LastVisit == nil {
+		return 0
+	}
+	return int(now.Sub(*p.LastVisit).Hours() / 24)
+}
+
+// OverdueBy returns how many of its usual gaps it's been since the last visit,
+// so 2 means twice as long as usual. Returns 0 if we don't know how often the
+// place gets visited
+func (p PlaceDetail) OverdueBy(now time.Time) float64 {
+	if p.Cadence == 0 || p.LastVisit == nil {
+		return 0
+	}
+	return float64(now.Sub(*p.LastVisit)) / float64(p.Cadence)
+}
+
+// Overdue returns the favorite places that have gone much longer than usual
+// without a visit, most overdue first
+func (p PlaceDetails) Overdue(now time.Time) PlaceDetails {
+	ret := PlaceDetails{}
+	for _, d := range p {
+		if d.AverageRating >= FavoriteRating && d.OverdueBy(now) >= OverdueRatio && !d.IsClosed() {
+			ret = append(ret, d)
+		}
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].OverdueBy(now) > ret[j].OverdueBy(now)
+	})
+	return ret
+}
+
+// OverdueBy returns the favorites that have gone much longer than usual without
+// a visit, grouped in the given way. It goes by every entry in the diary, not
+// just the filtered ones, since a favorite that's overdue may not have been
+// visited in the filtered dates at all, and its usual gap needs all its visits
+func (d Diary) OverdueBy(g Grouping, now time.Time) PlaceDetails {
+	all := d.unfilteredEntries.normalizeWith(d.normalization, d.unfilteredEntries)
+	return d.placeDetailsByOf(all, g).Overdue(now)
+}
@@ -0,0 +1,81 @@
+package letseat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCadence(t *testing.T) {
+	visits := func(dates ...string) Entries {
+		ret := Entries{}
+		for _, d := range dates {
+			ret = append(ret, Entry{Place: "Pizza Dude", Date: toPTR(mustTime(d)), Ratings: map[string]int{"drew": 5}})
+		}
+		return ret
+	}
+	weekly := visits("2024-01-05 19:00", "2024-01-12 18:00", "2024-01-19 19:30", "2024-01-19 21:00", "2024-02-02 19:00")
+	require.Equal(t, 7*24*time.Hour, weekly.cadence(), "same day visits count once, and the odd long gap is ignored")
+
+	// Lunch and a late dinner on the same local day, which are different days in UTC
+	est := time.FixedZone("EST", -5*60*60)
+	local := Entries{}
+	for _, d := range []time.Time{
+		time.Date(2024, 1, 5, 12, 0, 0, 0, est),
+		time.Date(2024, 1, 5, 21, 0, 0, 0, est),
+		time.Date(2024, 1, 12, 12, 0, 0, 0, est),
+		time.Date(2024, 1, 19, 12, 0, 0, 0, est),
+	} {
+		local = append(local, Entry{Place: "Pizza Dude", Date: toPTR(d)})
+	}
+	require.Equal(t, 7*24*time.Hour, local.cadence())
+
+	few := visits("2024-01-05 19:00", "2024-01-12 18:00")
+	require.Equal(t, time.Duration(0), few.cadence())
+
+	d := weekly.summarize("Pizza Dude")
+	require.Equal(t, 7, d.CadenceDays())
+	now := mustTime("2024-02-23 19:00")
+	require.Equal(t, 21, d.DaysSince(now))
+	require.Equal(t, 3.0, d.OverdueBy(now))
+	require.Equal(t, 0.0, PlaceDetail{}.OverdueBy(now))
+}
+
+func TestOverdue(t *testing.T) {
+	now := mustTime("2024-03-01 19:00")
+	last := func(s string) *time.Time { return toPTR(mustTime(s)) }
+	pd := PlaceDetails{
+		{Name: "On Schedule", AverageRating: 4.5, Cadence: 7 * day, LastVisit: last("2024-02-26 19:00")},
+		{Name: "Slipped Away", AverageRating: 4.5, Cadence: 7 * day, LastVisit: last("2024-02-01 19:00")},
+		{Name: "Bit Late", AverageRating: 4, Cadence: 14 * day, LastVisit: last("2024-02-01 19:00")},
+		{Name: "Meh", AverageRating: 3, Cadence: 7 * day, LastVisit: last("2024-01-01 19:00")},
+		{Name: "Unknown", AverageRating: 5, LastVisit: last("2023-01-01 19:00")},
+		{Name: "Gone", AverageRating: 5, Cadence: 7 * day, LastVisit: last("2024-01-01 19:00"), Place: &Place{Status: StatusClosed}},
+	}
+	names := []string{}
+	for _, d := range pd.Overdue(now) {
+		names = append(names, d.Name)
+	}
+	require.Equal(t, []string{"Slipped Away", "Bit Late"}, names)
+}
+
+func TestDiaryOverdueBy(t *testing.T) {
+	entries := Entries{}
+	for _, d := range []string{"2024-01-05 19:00", "2024-01-12 19:00", "2024-01-19 19:00", "2024-01-26 19:00"} {
+		entries = append(entries, Entry{Place: "Pizza Dude", Date: toPTR(mustTime(d)), Ratings: map[string]int{"drew": 5}})
+	}
+	entries = append(entries, Entry{Place: "Taco Tuesday", Date: toPTR(mustTime("2024-05-01 12:00")), Ratings: map[string]int{"drew": 3}})
+	now := mustTime("2024-05-26 19:00")
+	d := New(
+		WithDB(newTestDB(t)),
+		WithEntries(entries),
+		WithFilter(EntryFilter{Earliest: toPTR(now.AddDate(0, 0, -90))}),
+	)
+	require.Empty(t, d.PlaceDetails().Overdue(now), "the filtered dates miss Pizza Dude altogether")
+
+	got := d.OverdueBy(GroupByLocation, now)
+	require.Len(t, got, 1)
+	require.Equal(t, "Pizza Dude", got[0].Name)
+	require.Equal(t, 7, got[0].CadenceDays())
+}
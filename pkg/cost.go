@@ -0,0 +1,175 @@
+package letseat
+
+import (
+	"fmt"
+	"sort"
+)
+
+// CostReport breaks down what was spent. Only meals with a cost in the default
+// currency are counted
+type CostReport struct {
+	Currency  string       `yaml:"currency"`
+	Meals     int          `yaml:"meals"`
+	Total     Money        `yaml:"total"`
+	Average   Money        `yaml:"average"`
+	PerPerson Money        `yaml:"per_person"`
+	PerStar   Money        `yaml:"per_star"`
+	Skipped   int          `yaml:"skipped,omitempty"`
+	Places    []PlaceCost  `yaml:"places"`
+	Modes     []ModeCost   `yaml:"modes"`
+	Months    []PeriodCost `yaml:"months"`
+	Weeks     []PeriodCost `yaml:"weeks"`
+}
+
+// PlaceCost is what was spent at a single place
+type PlaceCost struct {
+	Place     string `yaml:"place"`
+	Meals     int    `yaml:"meals"`
+	Total     Money  `yaml:"total"`
+	Average   Money  `yaml:"average"`
+	PerPerson Money  `yaml:"per_person"`
+	PerStar   Money  `yaml:"per_star"`
+}
+
+// ModeCost is what was spent on a given service mode, like takeout
+type ModeCost struct {
+	Mode    Mode  `yaml:"mode"`
+	Meals   int   `yaml:"meals"`
+	Total   Money `yaml:"total"`
+	Average Money `yaml:"average"`
+}
+
+// PeriodCost is what was spent during a month or week
+type PeriodCost struct {
+	Period string `yaml:"period"`
+	Meals  int    `yaml:"meals"`
+	Total  Money  `yaml:"total"`
+}
+
+// costTally adds up the spend for a group of meals
+type costTally struct {
+	meals int
+	total int64
+	// diners and dinerTotal only count meals where we know who was there, for the cost per person
+	diners     int
+	dinerTotal int64
+	// rated and stars only count meals that someone rated, for the cost per star
+	rated int64
+	stars float64
+}
+
+func (c *costTally) add(e Entry, amount int64) {
+	c.meals++
+	c.total += amount
+	if n := len(e.Diners()); n > 0 {
+		c.diners += n
+		c.dinerTotal += amount
+	}
+	if len(e.rated()) > 0 {
+		c.rated += amount
+		c.stars += e.averageRating()
+	}
+}
+
+func (c costTally) money(amount int64) Money {
+	return NewMoney(amount, DefaultCurrency)
+}
+
+func (c costTally) average() Money {
+	return c.money(c.total).Div(c.meals)
+}
+
+func (c costTally) perPerson() Money {
+	return c.money(c.dinerTotal).Div(c.diners)
+}
+
+func (c costTally) perStar() Money {
+	if c.stars == 0 {
+		return c.money(0)
+	}
+	return c.money(int64(float64(c.rated)/c.stars + 0.5))
+}
+
+// CostReport breaks down what was spent on the entries
+func (e *Entries) CostReport() CostReport {
+	all := &costTally{}
+	places := map[string]*costTally{}
+	modes := map[Mode]*costTally{}
+	months := map[string]*costTally{}
+	weeks := map[string]*costTally{}
+	tally := func(m map[string]*costTally, k string) *costTally {
+		if _, ok := m[k]; !ok {
+			m[k] = &costTally{}
+		}
+		return m[k]
+	}
+
+	ret := CostReport{Currency: DefaultCurrency}
+	for _, entry := range *e {
+		if entry.Cost.IsZero() {
+			continue
+		}
+		total, err := entry.Cost.Total()
+		if err != nil || total.currency() != DefaultCurrency {
+			ret.Skipped++
+			continue
+		}
+		all.add(entry, total.Amount)
+		tally(places, entry.Place).add(entry, total.Amount)
+		if _, ok := modes[entry.Mode]; !ok {
+			modes[entry.Mode] = &costTally{}
+		}
+		modes[entry.Mode].add(entry, total.Amount)
+		if entry.Date != nil {
+			tally(months, entry.Date.Format("2006-01")).add(entry, total.Amount)
+			y, w := entry.Date.ISOWeek()
+			tally(weeks, fmt.Sprintf("%04d-W%02d", y, w)).add(entry, total.Amount)
+		}
+	}
+
+	ret.Meals = all.meals
+	ret.Total = all.money(all.total)
+	ret.Average = all.average()
+	ret.PerPerson = all.perPerson()
+	ret.PerStar = all.perStar()
+
+	ret.Places = make([]PlaceCost, 0, len(places))
+	for name, t := range places {
+		ret.Places = append(ret.Places, PlaceCost{
+			Place:     name,
+			Meals:     t.meals,
+			Total:     t.money(t.total),
+			Average:   t.average(),
+			PerPerson: t.perPerson(),
+			PerStar:   t.perStar(),
+		})
+	}
+	// Biggest spend first
+	sort.Slice(ret.Places, func(i, j int) bool {
+		if ret.Places[i].Total.Amount != ret.Places[j].Total.Amount {
+			return ret.Places[i].Total.Amount > ret.Places[j].Total.Amount
+		}
+		return ret.Places[i].Place < ret.Places[j].Place
+	})
+
+	ret.Modes = []ModeCost{}
+	for _, m := range append(AllModes, "") {
+		if t, ok := modes[m]; ok {
+			ret.Modes = append(ret.Modes, ModeCost{Mode: m, Meals: t.meals, Total: t.money(t.total), Average: t.average()})
+		}
+	}
+
+	ret.Months = periodCosts(months)
+	ret.Weeks = periodCosts(weeks)
+	return ret
+}
+
+// periodCosts returns the spend for each period, in order
+func periodCosts(m map[string]*costTally) []PeriodCost {
+	ret := make([]PeriodCost, 0, len(m))
+	for k, t := range m {
+		ret = append(ret, PeriodCost{Period: k, Meals: t.meals, Total: t.money(t.total)})
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Period < ret[j].Period })
+	return ret
+}
@@ -0,0 +1,86 @@
+package letseat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCostReport(t *testing.T) {
+	entries := Entries{
+		{
+			Place:   "Pizza Dude",
+			Mode:    ModeDineIn,
+			Date:    toPTR(mustTime("2024-01-05 19:00")),
+			Cost:    Bill{Subtotal: MustParseMoney("40", "USD"), Tip: MustParseMoney("8", "USD")},
+			Ratings: map[string]int{"drew": 4, "james": 2},
+		},
+		{
+			Place:   "Pizza Dude",
+			Mode:    ModeTakeout,
+			Date:    toPTR(mustTime("2024-01-12 18:00")),
+			Cost:    Bill{Subtotal: MustParseMoney("20", "USD")},
+			Ratings: map[string]int{"drew": 5},
+		},
+		{
+			Place:   "Taco Tuesday",
+			Mode:    ModeTakeout,
+			Date:    toPTR(mustTime("2024-02-06 12:00")),
+			Cost:    Bill{Subtotal: MustParseMoney("12", "USD")},
+			Ratings: map[string]int{"drew": 3},
+		},
+		{Place: "Taco Tuesday", Date: toPTR(mustTime("2024-02-13 12:00")), Ratings: map[string]int{"drew": 3}},
+		{Place: "Le Bistro", Date: toPTR(mustTime("2024-02-14 20:00")), Cost: Bill{Subtotal: MustParseMoney("80", "EUR")}},
+	}
+	got := entries.CostReport()
+	require.Equal(t, 3, got.Meals)
+	require.Equal(t, 1, got.Skipped, "other currencies are skipped")
+	require.Equal(t, "$80.00", got.Total.String())
+	require.Equal(t, "$26.67", got.Average.String())
+	require.Equal(t, "$20.00", got.PerPerson.String())
+	// $80 over 3 + 5 + 3 stars
+	require.Equal(t, "$7.27", got.PerStar.String())
+
+	require.Equal(t, []PlaceCost{
+		{
+			Place: "Pizza Dude", Meals: 2,
+			Total:     MustParseMoney("68", "USD"),
+			Average:   MustParseMoney("34", "USD"),
+			PerPerson: MustParseMoney("22.67", "USD"),
+			PerStar:   MustParseMoney("8.50", "USD"),
+		},
+		{
+			Place: "Taco Tuesday", Meals: 1,
+			Total:     MustParseMoney("12", "USD"),
+			Average:   MustParseMoney("12", "USD"),
+			PerPerson: MustParseMoney("12", "USD"),
+			PerStar:   MustParseMoney("4", "USD"),
+		},
+	}, got.Places)
+	require.Equal(t, []ModeCost{
+		{Mode: ModeDineIn, Meals: 1, Total: MustParseMoney("48", "USD"), Average: MustParseMoney("48", "USD")},
+		{Mode: ModeTakeout, Meals: 2, Total: MustParseMoney("32", "USD"), Average: MustParseMoney("16", "USD")},
+	}, got.Modes)
+	require.Equal(t, []PeriodCost{
+		{Period: "2024-01", Meals: 2, Total: MustParseMoney("68", "USD")},
+		{Period: "2024-02", Meals: 1, Total: MustParseMoney("12", "USD")},
+	}, got.Months)
+	require.Equal(t, []string{"2024-W01", "2024-W02", "2024-W06"}, []string{got.Weeks[0].Period, got.Weeks[1].Period, got.Weeks[2].Period})
+
+	empty := Entries{}
+	require.Equal(t, "$0.00", empty.CostReport().PerStar.String())
+}
+
+func TestCostReportPerPerson(t *testing.T) {
+	usd := func(s string) Bill { return Bill{Subtotal: MustParseMoney(s, "USD")} }
+	entries := Entries{
+		{Place: "Pizza Dude", Cost: usd("30"), Attendees: []string{"drew", "james", "layla"}},
+		// Older entries only know about raters, and gave everyone else a 0
+		{Place: "Pizza Dude", Cost: usd("20"), Ratings: map[string]int{"drew": 4, "james": 0}},
+		{Place: "Pizza Dude", Cost: usd("50"), Ratings: map[string]int{"james": 0}},
+	}
+	got := entries.CostReport()
+	require.Equal(t, "$100.00", got.Total.String())
+	require.Equal(t, "$12.50", got.PerPerson.String(), "$50 over 4 diners, skipping the meal nobody is known to have been at")
+	require.Equal(t, "$5.00", got.PerStar.String(), "only the meal with a real rating counts")
+}
@@ -0,0 +1,143 @@
+package letseat
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateRange is a span of time. Either end can be nil, meaning it's open ended
+type DateRange struct {
+	Start *time.Time
+	End   *time.Time
+}
+
+var calendarDuration = regexp.MustCompile(`^(\d+(?:y|mo|w|d))+$`)
+
+var calendarPart = regexp.MustCompile(`(\d+)(y|mo|w|d)`)
+
+// ParseDateRange reads the dates people actually type, relative to now. It understands:
+//
+//   - durations back from now, like "90d", "6mo", "1y" or "1y6mo"
+//   - absolute dates, like "2023-06-01", which cover that whole day
+//   - "since 2023-01-01" and "until 2023-06-01"
+//   - "today", "yesterday", and "this" or "last" week, month or year
+//
+// The range is in UTC, the same as the entries, so a date covers the same
+// calendar day no matter where now is
+func ParseDateRange(s string, now time.Time) (DateRange, error) {
+	k := strings.Join(strings.Fields(strings.ToLower(s)), " ")
+	now = entryTime(now)
+	day := startOfDay(now)
+	switch {
+	case k == "":
+		return DateRange{}, fmt.Errorf("date cannot be empty")
+	case k == "today":
+		return dayRange(day), nil
+	case k == "yesterday":
+		return dayRange(day.AddDate(0, 0, -1)), nil
+	case strings.HasPrefix(k, "since "):
+		d, err := parseDay(strings.TrimPrefix(k, "since "), now)
+		if err != nil {
+			return DateRange{}, err
+		}
+		return DateRange{Start: &d}, nil
+	case strings.HasPrefix(k, "until "), strings.HasPrefix(k, "before "):
+		_, rest, _ := strings.Cut(k, " ")
+		d, err := parseDay(rest, now)
+		if err != nil {
+			return DateRange{}, err
+		}
+		return DateRange{End: toPTR(endOf(d, 0, 0, 1))}, nil
+	case strings.HasPrefix(k, "this "), strings.HasPrefix(k, "last "):
+		which, unit, _ := strings.Cut(k, " ")
+		return periodRange(unit, which == "last", day)
+	case calendarDuration.MatchString(k):
+		start := day
+		for _, part := range calendarPart.FindAllStringSubmatch(k, -1) {
+			n, err := strconv.Atoi(part[1])
+			if err != nil {
+				return DateRange{}, err
+			}
+			switch part[2] {
+			case "y":
+				start = start.AddDate(-n, 0, 0)
+			case "mo":
+				start = start.AddDate(0, -n, 0)
+			case "w":
+				start = start.AddDate(0, 0, -7*n)
+			case "d":
+				start = start.AddDate(0, 0, -n)
+			}
+		}
+		// Durations keep the time of day, like they always have
+		start = start.Add(now.Sub(day))
+		return DateRange{Start: &start}, nil
+	}
+	if d, err := parseDay(k, now); err == nil {
+		return dayRange(d), nil
+	}
+	if d, err := ParseDuration(k); err == nil {
+		return DateRange{Start: toPTR(now.Add(-d))}, nil
+	}
+	return DateRange{}, fmt.Errorf("unknown date: %v, try something like 90d, 2023-06-01 or last month", s)
+}
+
+// IsPeriod returns true if s names a whole period, like "today" or "last
+// month", instead of just a point in time to start or end at
+func IsPeriod(s string) bool {
+	k := strings.Join(strings.Fields(strings.ToLower(s)), " ")
+	return k == "today" || k == "yesterday" || strings.HasPrefix(k, "this ") || strings.HasPrefix(k, "last ")
+}
+
+// entryTime moves t in to UTC, keeping the same wall clock time. Entries are
+// stored that way, with a date at UTC midnight meaning that day wherever it was
+func entryTime(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// parseDay reads a date like "2023-06-01", in the same location as now
+func parseDay(s string, now time.Time) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02", strings.TrimSpace(s), now.Location())
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// endOf returns the last moment before start plus the given years, months and days
+func endOf(start time.Time, years, months, days int) time.Time {
+	return start.AddDate(years, months, days).Add(-time.Nanosecond)
+}
+
+func dayRange(d time.Time) DateRange {
+	return DateRange{Start: &d, End: toPTR(endOf(d, 0, 0, 1))}
+}
+
+// periodRange returns the whole week, month or year that day is in, or the one before it
+func periodRange(unit string, last bool, day time.Time) (DateRange, error) {
+	var start time.Time
+	var years, months, days int
+	switch unit {
+	case "week":
+		// Weeks start on Monday
+		start = day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		days = 7
+	case "month":
+		start = time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+		months = 1
+	case "year":
+		start = time.Date(day.Year(), 1, 1, 0, 0, 0, 0, day.Location())
+		years = 1
+	default:
+		return DateRange{}, fmt.Errorf("unknown period: %v, must be one of week, month or year", unit)
+	}
+	if last {
+		start = start.AddDate(-years, -months, -days)
+	}
+	return DateRange{Start: &start, End: toPTR(endOf(start, years, months, days))}, nil
+}
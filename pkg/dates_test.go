@@ -0,0 +1,83 @@
+package letseat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDateRange(t *testing.T) {
+	// A Wednesday
+	now := mustTime("2024-03-13 18:30")
+	tests := map[string]struct {
+		given       string
+		expectStart string
+		expectEnd   string
+		expectErr   string
+	}{
+		"days":         {given: "90d", expectStart: "2023-12-14 18:30"},
+		"months":       {given: "6mo", expectStart: "2023-09-13 18:30"},
+		"years":        {given: "1y", expectStart: "2023-03-13 18:30"},
+		"combined":     {given: "1y2mo", expectStart: "2023-01-13 18:30"},
+		"hours":        {given: "36h", expectStart: "2024-03-12 06:30"},
+		"absolute":     {given: "2023-06-01", expectStart: "2023-06-01 00:00", expectEnd: "2023-06-01 23:59"},
+		"since":        {given: "since 2023-01-01", expectStart: "2023-01-01 00:00"},
+		"until":        {given: "until 2023-01-31", expectEnd: "2023-01-31 23:59"},
+		"today":        {given: "today", expectStart: "2024-03-13 00:00", expectEnd: "2024-03-13 23:59"},
+		"yesterday":    {given: "Yesterday", expectStart: "2024-03-12 00:00", expectEnd: "2024-03-12 23:59"},
+		"this-week":    {given: "this week", expectStart: "2024-03-11 00:00", expectEnd: "2024-03-17 23:59"},
+		"last-week":    {given: "last week", expectStart: "2024-03-04 00:00", expectEnd: "2024-03-10 23:59"},
+		"last-month":   {given: "last  month", expectStart: "2024-02-01 00:00", expectEnd: "2024-02-29 23:59"},
+		"this-year":    {given: "this year", expectStart: "2024-01-01 00:00", expectEnd: "2024-12-31 23:59"},
+		"last-year":    {given: "last year", expectStart: "2023-01-01 00:00", expectEnd: "2023-12-31 23:59"},
+		"bad-period":   {given: "last fortnight", expectErr: "unknown period: fortnight, must be one of week, month or year"},
+		"bad-since":    {given: "since forever", expectErr: `parsing time "forever" as "2006-01-02": cannot parse "forever" as "2006"`},
+		"not-a-date":   {given: "whenever", expectErr: "unknown date: whenever, try something like 90d, 2023-06-01 or last month"},
+		"empty-string": {given: "", expectErr: "date cannot be empty"},
+	}
+	format := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.Format("2006-01-02 15:04")
+	}
+	for desc, tt := range tests {
+		got, err := ParseDateRange(tt.given, now)
+		if tt.expectErr != "" {
+			require.EqualError(t, err, tt.expectErr, desc)
+			continue
+		}
+		require.NoError(t, err, desc)
+		require.Equal(t, tt.expectStart, format(got.Start), desc)
+		require.Equal(t, tt.expectEnd, format(got.End), desc)
+	}
+}
+
+func TestIsPeriod(t *testing.T) {
+	for _, given := range []string{"today", "Yesterday", "this week", "last  month"} {
+		require.True(t, IsPeriod(given), given)
+	}
+	for _, given := range []string{"90d", "2023-06-01", "since 2023-01-01", "until 2023-01-31"} {
+		require.False(t, IsPeriod(given), given)
+	}
+}
+
+func TestParseDateRangeNotUTC(t *testing.T) {
+	// Entries are stored at UTC midnight, wherever they were logged
+	entry := mustTime("2023-12-14 00:00")
+	now := time.Date(2023, 12, 20, 10, 0, 0, 0, time.FixedZone("EST", -5*60*60))
+
+	got, err := ParseDateRange("2023-12-14", now)
+	require.NoError(t, err)
+	require.False(t, entry.Before(*got.Start))
+	require.False(t, entry.After(*got.End))
+
+	got, err = ParseDateRange("this year", now)
+	require.NoError(t, err)
+	require.Equal(t, mustTime("2023-01-01 00:00"), *got.Start)
+
+	got, err = ParseDateRange("6d", now)
+	require.NoError(t, err)
+	require.Equal(t, mustTime("2023-12-14 10:00"), *got.Start)
+}
@@ -4,13 +4,16 @@ Package letseat is the main thing that decides where to go for dindin
 package letseat
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"slices"
 	"sort"
 	"time"
 
+	"github.com/gosimple/slug"
 	"github.com/montanaflynn/stats"
 	bolt "go.etcd.io/bbolt"
 	"gopkg.in/yaml.v2"
@@ -22,6 +25,7 @@ type Diary struct {
 	unfilteredEntries Entries
 	entries           *Entries
 	filter            EntryFilter
+	normalization     Normalization
 	db                *bolt.DB
 }
 
@@ -75,13 +79,13 @@ func (d Diary) allEntries() (Entries, error) {
 	return ret, nil
 }
 
-// Export returns all entries in yaml form
+// Export returns all the entries matching the filter in yaml form
 func (d Diary) Export() ([]byte, error) {
 	entries, err := d.allEntries()
 	if err != nil {
 		return nil, err
 	}
-	return yaml.Marshal(entries)
+	return yaml.Marshal(entries.filter(&d.filter))
 }
 
 // Log logs a new entry to your diary
@@ -96,6 +100,9 @@ func (d *Diary) Log(es ...Entry) error {
 				slog.Warn("error logging entry", "error", err)
 			}
 			*d.entries = append(*d.entries, e)
+			if err := registerPlace(tx, e.Place); err != nil {
+				slog.Warn("error registering place", "error", err)
+			}
 		}
 		// Now save the person info
 		for _, person := range d.entries.PeopleEnhanced() {
@@ -110,20 +117,106 @@ func (d *Diary) Log(es ...Entry) error {
 	return nil
 }
 
+// registerPlace adds a place to the places bucket, if it isn't already there
+func registerPlace(tx *bolt.Tx, name string) error {
+	if name == "" {
+		return nil
+	}
+	p, err := NewPlace(WithName(name))
+	if err != nil {
+		return err
+	}
+	b := tx.Bucket([]byte(PlacesBucket))
+	if b.Get([]byte(p.Slug)) != nil {
+		return nil
+	}
+	return b.Put([]byte(p.Slug), p.mustMarshal())
+}
+
+// Places returns all the places in the registry
+func (d Diary) Places() (Places, error) {
+	ret := Places{}
+	if verr := d.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(PlacesBucket)).ForEach(func(_, v []byte) error {
+			var p Place
+			if err := json.Unmarshal(v, &p); err != nil {
+				return err
+			}
+			ret = append(ret, p)
+			return nil
+		})
+	}); verr != nil {
+		return nil, verr
+	}
+	return ret, nil
+}
+
+// GetPlace returns a place from the registry by its name
+func (d Diary) GetPlace(name string) (*Place, error) {
+	places, err := d.Places()
+	if err != nil {
+		return nil, err
+	}
+	p := places.Find(name)
+	if p == nil {
+		return nil, fmt.Errorf("place not found: %v", name)
+	}
+	return p, nil
+}
+
+// SavePlace writes a place to the registry, replacing any existing place with the same slug
+func (d *Diary) SavePlace(p Place) error {
+	if p.Name == "" {
+		return errors.New("name cannot be empty")
+	}
+	if p.Slug == "" {
+		p.Slug = slug.Make(p.Name)
+	}
+	return d.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(PlacesBucket)).Put([]byte(p.Slug), p.mustMarshal())
+	})
+}
+
 // MostPopularPlace just returns the most popular place
 func (d Diary) MostPopularPlace() string {
 	return mostFrequent(d.entries.placeNames())
 }
 
+// PlaceDetail returns the details of a single place, using every entry in the
+// diary, not just the filtered ones
+func (d Diary) PlaceDetail(name string) (*PlaceDetail, error) {
+	all, err := d.allEntries()
+	if err != nil {
+		return nil, err
+	}
+	ret := all.placeDetails(name)
+	registry, err := d.Places()
+	if err != nil {
+		return nil, err
+	}
+	ret.Place = registry.Find(name)
+	return ret, nil
+}
+
 // PlaceDetails is just some detail summary pieces of the places in your diary
 func (d Diary) PlaceDetails() PlaceDetails {
-	e := d.Entries()
+	return d.placeDetailsOf(d.Entries())
+}
+
+// placeDetailsOf summarizes each place in the given entries
+func (d Diary) placeDetailsOf(e Entries) PlaceDetails {
+	registry, err := d.Places()
+	if err != nil {
+		slog.Warn("error reading places", "error", err)
+	}
 	places := e.UniquePlaceNames()
 	ret := make(PlaceDetails, len(places))
 	for idx, place := range places {
 		d := e.placeDetails(place)
+		d.Place = registry.Find(place)
 		ret[idx] = *d
 	}
+	ret.Score()
 	return ret
 }
 
@@ -195,7 +288,15 @@ func New(opts ...func(*Diary)) *Diary {
 		opt(d)
 	}
 
+	if d.db != nil {
+		places, err := d.Places()
+		if err != nil {
+			slog.Warn("error reading places", "error", err)
+		}
+		d.filter.places = places
+	}
 	d.entries = toPTR(d.unfilteredEntries.filter(&d.filter))
+	d.entries = toPTR(d.entries.normalizeWith(d.normalization, d.unfilteredEntries))
 	return d
 }
 
@@ -221,7 +322,37 @@ func initDB(db *bolt.DB) error {
 			return err
 		}
 	}
-	return nil
+	return migrateDB(db)
+}
+
+// migrateDB rewrites any entries that were stored using an older layout, and
+// makes sure every place in the diary is in the place registry
+func migrateDB(db *bolt.DB) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(EntriesBucket))
+		updates := map[string][]byte{}
+		if err := b.ForEach(func(k, v []byte) error {
+			var e Entry
+			if err := json.Unmarshal(v, &e); err != nil {
+				return err
+			}
+			if err := registerPlace(tx, e.Place); err != nil {
+				return err
+			}
+			if migrated := e.mustMarshal(); !bytes.Equal(migrated, v) {
+				updates[string(k)] = migrated
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		for k, v := range updates {
+			if err := b.Put([]byte(k), v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 /*
@@ -252,11 +383,112 @@ type Entries []Entry
 
 // Entry represents a log about your visit to a restaurant
 type Entry struct {
-	Place     string         `yaml:"place"`
-	Cost      int            `yaml:"cost,omitempty"`
-	Date      *time.Time     `yaml:"date"`
-	IsTakeout bool           `yaml:"takeout,omitempty"`
-	Ratings   map[string]int `yaml:"ratings,omitempty"`
+	Place       string         `yaml:"place"`
+	Cost        Bill           `yaml:"cost,omitempty"`
+	Date        *time.Time     `yaml:"date"`
+	Meal        MealType       `yaml:"meal,omitempty"`
+	Mode        Mode           `yaml:"mode,omitempty"`
+	Platform    string         `yaml:"platform,omitempty"`
+	Attendees   []string       `yaml:"attendees,omitempty"`
+	Guests      []string       `yaml:"guests,omitempty"`
+	Ratings     map[string]int `yaml:"ratings,omitempty"`
+	Attachments []Attachment   `yaml:"attachments,omitempty"`
+	// adjusted are the normalized ratings, if the entries have been normalized
+	adjusted map[string]float64
+}
+
+// HasTime returns true if we know what time of day the meal was
+func (d Entry) HasTime() bool {
+	if d.Date == nil {
+		return false
+	}
+	h, m, s := d.Date.Clock()
+	return h != 0 || m != 0 || s != 0
+}
+
+// MealType returns the type of meal, guessing from the time of day if it wasn't set
+func (d Entry) MealType() MealType {
+	if d.Meal != "" {
+		return d.Meal
+	}
+	if d.HasTime() {
+		return MealTypeAt(*d.Date)
+	}
+	return ""
+}
+
+// Attended returns true if the person was at the meal. Older entries don't
+// have attendees, so anyone who rated the meal is assumed to have been there.
+// Those entries also gave everyone a 0 rating, which doesn't count
+func (d Entry) Attended(name string) bool {
+	if slices.Contains(d.Guests, name) {
+		return true
+	}
+	if len(d.Attendees) > 0 {
+		return slices.Contains(d.Attendees, name)
+	}
+	return d.Ratings[name] != 0
+}
+
+// Diners returns everyone who was at the meal, including guests
+func (d Entry) Diners() []string {
+	ret := []string{}
+	if len(d.Attendees) > 0 {
+		ret = append(ret, d.Attendees...)
+	} else {
+		for name, r := range d.Ratings {
+			if r != 0 && !slices.Contains(d.Guests, name) {
+				ret = append(ret, name)
+			}
+		}
+		sort.Strings(ret)
+	}
+	for _, guest := range d.Guests {
+		if !slices.Contains(ret, guest) {
+			ret = append(ret, guest)
+		}
+	}
+	return ret
+}
+
+// entryAlias lets us unmarshal an Entry without recursing in to the custom unmarshalers
+type entryAlias Entry
+
+// legacyEntry is an Entry along with the fields that older diaries used
+type legacyEntry struct {
+	entryAlias `yaml:",inline"`
+	IsTakeout  *bool `yaml:"takeout,omitempty"`
+}
+
+// UnmarshalYAML reads an entry, migrating any fields from older diaries
+func (d *Entry) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var l legacyEntry
+	if err := unmarshal(&l); err != nil {
+		return err
+	}
+	*d = l.migrate()
+	return nil
+}
+
+// UnmarshalJSON reads an entry, migrating any fields from older databases
+func (d *Entry) UnmarshalJSON(b []byte) error {
+	var l legacyEntry
+	if err := json.Unmarshal(b, &l); err != nil {
+		return err
+	}
+	*d = l.migrate()
+	return nil
+}
+
+func (l legacyEntry) migrate() Entry {
+	e := Entry(l.entryAlias)
+	if e.Mode == "" {
+		e.Mode = ModeDineIn
+		if l.IsTakeout != nil && *l.IsTakeout {
+			e.Mode = ModeTakeout
+		}
+	}
+	return e
 }
 
 // Key is the key path for the database for a given entry
@@ -275,16 +507,39 @@ func (d Entry) mustMarshal() []byte {
 	return got
 }
 
+// ratingValuesAsFloat64 returns the ratings people actually gave, so averages
+// aren't dragged down by "No Rating"
 func (d *Entry) ratingValuesAsFloat64() []float64 {
-	ret := make([]float64, len(d.Ratings))
-	idx := 0
-	for _, v := range d.Ratings {
-		ret[idx] = float64(v)
-		idx++
+	ret := make([]float64, 0, len(d.Ratings))
+	for name := range d.rated() {
+		r, _ := d.rating(name)
+		ret = append(ret, r)
 	}
 	return ret
 }
 
+// rated returns the ratings people actually gave, leaving out the 0s that mean
+// "No Rating"
+func (d Entry) rated() map[string]int {
+	ret := map[string]int{}
+	for name, r := range d.Ratings {
+		if r != 0 {
+			ret[name] = r
+		}
+	}
+	return ret
+}
+
+// rating returns what someone rated the meal, normalized if the entry has been.
+// Returns false if they didn't rate it, including a 0 for "No Rating"
+func (d *Entry) rating(name string) (float64, bool) {
+	if r, ok := d.adjusted[name]; ok {
+		return r, true
+	}
+	r := d.Ratings[name]
+	return float64(r), r != 0
+}
+
 func (d *Entry) averageRating() float64 {
 	r := d.ratingValuesAsFloat64()
 	if len(r) == 0 {
@@ -297,22 +552,51 @@ func (d *Entry) averageRating() float64 {
 	return m
 }
 
+// TotalCost returns the total cost of all the entries, by currency
+func (e *Entries) TotalCost() Totals {
+	t := Totals{}
+	for _, entry := range *e {
+		t.Merge(entry.Cost.Totals())
+	}
+	return t
+}
+
 // EntryFilter defiines how to filter a list of entries
 type EntryFilter struct {
-	Place       string
-	OnlyTakeout bool
-	OnlyDineIn  bool
-	Earliest    *time.Time
-	Latest      *time.Time
+	Place         string
+	Places        []string
+	ExcludePlaces []string
+	Modes         []Mode
+	Meals         []MealType
+	Weekdays      []time.Weekday
+	Earliest      *time.Time
+	Latest        *time.Time
+	MaxPrice      PriceLevel
+	// With only includes entries where all of these people were there
+	With      []string
+	MinRating *float64
+	MaxRating *float64
+	MinCost   *Money
+	MaxCost   *Money
+	Where     *Where
+	// places is the registry, for filtering on things we know about the place
+	places Places
 }
 
+// people returns the regulars in the entries. Guests are left out, even if they rated something
 func (e *Entries) people() []string {
 	people := []string{}
+	add := func(entry Entry, person string) {
+		if !slices.Contains(people, person) && !slices.Contains(entry.Guests, person) {
+			people = append(people, person)
+		}
+	}
 	for _, entry := range *e {
+		for _, person := range entry.Attendees {
+			add(entry, person)
+		}
 		for person := range entry.Ratings {
-			if !slices.Contains(people, person) {
-				people = append(people, person)
-			}
+			add(entry, person)
 		}
 	}
 
@@ -330,18 +614,21 @@ func (e *Entries) PeopleEnhanced() []Person {
 			Name:            name,
 			PlaceAvgRatings: map[string]float64{},
 		}
-		ratings := map[string][]int{}
+		ratings := map[string][]float64{}
 		// Parse through diary ratings
 		for _, entry := range *e {
-			if entry.Ratings[name] != 0 {
-				ratings[entry.Place] = append(ratings[entry.Place], entry.Ratings[name])
+			if entry.Attended(name) {
+				p.Visits++
+			}
+			if r, ok := entry.rating(name); ok {
+				ratings[entry.Place] = append(ratings[entry.Place], r)
 			}
 		}
 		for k, v := range ratings {
 			var total float64
 			total = 0
 			for _, number := range v {
-				total += float64(number)
+				total += number
 			}
 			p.PlaceAvgRatings[k] = total / float64(len(v))
 		}
@@ -374,16 +661,33 @@ func (e *Entries) UniquePlaceNames() []string {
 
 func (e *Entries) placeDetails(place string) *PlaceDetail {
 	f := e.filter(&EntryFilter{Place: place})
+	return f.summarize(place)
+}
+
+// summarize returns the details of the entries, under the given name
+func (e *Entries) summarize(name string) *PlaceDetail {
 	dets := &PlaceDetail{
-		Name:          place,
-		AverageRating: f.averageRating(),
-		Visits:        len(f),
+		Name:          name,
+		AverageRating: e.averageRating(),
+		Visits:        len(*e),
 	}
+	if m, ok := e.MedianCostPerPerson(); ok {
+		dets.MedianCost = &m
+		dets.ActualPrice = PriceLevelFor(m)
+	}
+	dets.Trend = e.RatingTrend()
+	dets.Cadence = e.cadence()
 
-	for _, entry := range f {
+	for _, entry := range *e {
 		if dets.LastVisit == nil || entry.Date.After(*dets.LastVisit) {
 			dets.LastVisit = entry.Date
 		}
+		if dets.FirstVisit == nil || entry.Date.Before(*dets.FirstVisit) {
+			dets.FirstVisit = entry.Date
+		}
+		if len(entry.rated()) > 0 {
+			dets.ratings = append(dets.ratings, entry.averageRating())
+		}
 	}
 	return dets
 }
@@ -404,28 +708,107 @@ func (e *Entries) filter(f *EntryFilter) Entries {
 
 	filtered := Entries{}
 	for _, entry := range *e {
-		if f.OnlyTakeout && !entry.IsTakeout {
-			continue
+		if f.Match(entry) {
+			filtered = append(filtered, entry)
 		}
+	}
 
-		if f.OnlyDineIn && entry.IsTakeout {
-			continue
-		}
+	return filtered
+}
 
-		if f.Place != "" && entry.Place != f.Place {
-			continue
-		}
+// Match returns true if the entry makes it through the filter
+func (f EntryFilter) Match(entry Entry) bool {
+	if len(f.Modes) > 0 && !slices.Contains(f.Modes, entry.Mode) {
+		return false
+	}
 
-		if f.Earliest != nil && entry.Date.Before(*f.Earliest) {
-			continue
+	if len(f.Meals) > 0 && !slices.Contains(f.Meals, entry.MealType()) {
+		return false
+	}
+
+	if f.Place != "" && entry.Place != f.Place {
+		return false
+	}
+
+	if len(f.Places) > 0 && !slices.ContainsFunc(f.Places, func(p string) bool { return samePlace(p, entry.Place) }) {
+		return false
+	}
+
+	if slices.ContainsFunc(f.ExcludePlaces, func(p string) bool { return samePlace(p, entry.Place) }) {
+		return false
+	}
+
+	if f.MaxPrice > 0 {
+		if p := f.places.Find(entry.Place); p != nil && p.Price > f.MaxPrice {
+			return false
 		}
+	}
+
+	if f.Earliest != nil && entry.Date.Before(*f.Earliest) {
+		return false
+	}
+
+	if f.Latest != nil && entry.Date.After(*f.Latest) {
+		return false
+	}
 
-		if f.Latest != nil && entry.Date.After(*f.Latest) {
-			continue
+	if len(f.Weekdays) > 0 && (entry.Date == nil || !slices.Contains(f.Weekdays, entry.Date.Weekday())) {
+		return false
+	}
+
+	for _, name := range f.With {
+		if !entry.Attended(name) {
+			return false
 		}
+	}
 
-		filtered = append(filtered, entry)
+	if f.Where != nil && !f.Where.Match(entry, f.places) {
+		return false
 	}
 
-	return filtered
+	return f.matchRating(entry) && f.matchCost(entry)
+}
+
+// matchRating checks the average rating. Entries nobody rated never match a rating range
+func (f EntryFilter) matchRating(entry Entry) bool {
+	if f.MinRating == nil && f.MaxRating == nil {
+		return true
+	}
+	if len(entry.rated()) == 0 {
+		return false
+	}
+	r := entry.averageRating()
+	if f.MinRating != nil && r < *f.MinRating {
+		return false
+	}
+	return f.MaxRating == nil || r <= *f.MaxRating
+}
+
+// matchCost checks the total cost. Entries without a cost, or in some other
+// currency, never match a cost range
+func (f EntryFilter) matchCost(entry Entry) bool {
+	if f.MinCost == nil && f.MaxCost == nil {
+		return true
+	}
+	if entry.Cost.IsZero() {
+		return false
+	}
+	total, err := entry.Cost.Total()
+	if err != nil {
+		return false
+	}
+	for _, limit := range []*Money{f.MinCost, f.MaxCost} {
+		if limit != nil && limit.currency() != total.currency() {
+			return false
+		}
+	}
+	if f.MinCost != nil && total.Amount < f.MinCost.Amount {
+		return false
+	}
+	return f.MaxCost == nil || total.Amount <= f.MaxCost.Amount
+}
+
+// samePlace compares place names the same way the registry does, by slug
+func samePlace(a, b string) bool {
+	return slug.Make(a) == slug.Make(b)
 }
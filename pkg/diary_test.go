@@ -1,6 +1,7 @@
 package letseat
 
 import (
+	"encoding/json"
 	"path"
 	"testing"
 	"time"
@@ -49,28 +50,28 @@ func TestFilterDiary(t *testing.T) {
 	}{
 		{
 			diary: Entries{
-				Entry{Place: "A", IsTakeout: true},
-				Entry{Place: "B", IsTakeout: false},
+				Entry{Place: "A", Mode: ModeTakeout},
+				Entry{Place: "B", Mode: ModeDineIn},
 			},
 			filter: &EntryFilter{
-				OnlyTakeout: true,
+				Modes: []Mode{ModeTakeout},
 			},
 			want: 1,
 		},
 		{
 			diary: Entries{
-				Entry{Place: "A", IsTakeout: true},
-				Entry{Place: "B", IsTakeout: false},
+				Entry{Place: "A", Mode: ModeTakeout},
+				Entry{Place: "B", Mode: ModeDineIn},
 			},
 			filter: &EntryFilter{
-				OnlyDineIn: true,
+				Modes: []Mode{ModeDineIn},
 			},
 			want: 1,
 		},
 		{
 			diary: Entries{
-				Entry{Place: "A", IsTakeout: true},
-				Entry{Place: "B", IsTakeout: false},
+				Entry{Place: "A", Mode: ModeTakeout},
+				Entry{Place: "B", Mode: ModeDineIn},
 			},
 			filter: &EntryFilter{
 				Place: "A",
@@ -79,8 +80,8 @@ func TestFilterDiary(t *testing.T) {
 		},
 		{
 			diary: Entries{
-				Entry{Place: "A", IsTakeout: true},
-				Entry{Place: "B", IsTakeout: false},
+				Entry{Place: "A", Mode: ModeTakeout},
+				Entry{Place: "B", Mode: ModeDineIn},
 			},
 			want: 2,
 		},
@@ -91,6 +92,47 @@ func TestFilterDiary(t *testing.T) {
 	}
 }
 
+func TestEntryFilterMatch(t *testing.T) {
+	fri := mustTime("2024-01-05 19:00")
+	entries := Entries{
+		{
+			Place: "Taco Tuesday", Date: &fri, Attendees: []string{"drew", "andrei"},
+			Ratings: map[string]int{"drew": 4, "andrei": 5},
+			Cost:    Bill{Subtotal: MustParseMoney("30", "USD")},
+		},
+		{
+			Place: "Pizza Dude", Date: toPTR(fri.AddDate(0, 0, 1)), Attendees: []string{"drew"},
+			Ratings: map[string]int{"drew": 2},
+			Cost:    Bill{Subtotal: MustParseMoney("12", "USD")},
+		},
+		{
+			Place: "Fancy Spot", Date: toPTR(fri.AddDate(0, 0, 3)), Attendees: []string{"andrei"},
+			Cost: Bill{Subtotal: MustParseMoney("80", "EUR")},
+		},
+		// Older entries gave everyone a 0 rating, which means "No Rating"
+		{Place: "Old Diner", Date: toPTR(fri.AddDate(0, 0, 2)), Ratings: map[string]int{"drew": 4, "andrei": 0}},
+		{Place: "Ghost Kitchen", Date: toPTR(fri.AddDate(0, 0, 2)), Ratings: map[string]int{"andrei": 0}},
+	}
+	tests := map[string]struct {
+		given  EntryFilter
+		expect []string
+	}{
+		"with":           {given: EntryFilter{With: []string{"andrei"}}, expect: []string{"Fancy Spot", "Taco Tuesday"}},
+		"with-everyone":  {given: EntryFilter{With: []string{"andrei", "drew"}}, expect: []string{"Taco Tuesday"}},
+		"min-rating":     {given: EntryFilter{MinRating: toPTR(4.0)}, expect: []string{"Old Diner", "Taco Tuesday"}},
+		"max-rating":     {given: EntryFilter{MaxRating: toPTR(3.0)}, expect: []string{"Pizza Dude"}},
+		"min-cost":       {given: EntryFilter{MinCost: toPTR(MustParseMoney("20", "USD"))}, expect: []string{"Taco Tuesday"}},
+		"max-cost":       {given: EntryFilter{MaxCost: toPTR(MustParseMoney("20", "USD"))}, expect: []string{"Pizza Dude"}},
+		"weekdays":       {given: EntryFilter{Weekdays: []time.Weekday{time.Friday, time.Monday}}, expect: []string{"Fancy Spot", "Taco Tuesday"}},
+		"places":         {given: EntryFilter{Places: []string{"taco tuesday", "Pizza Dude"}}, expect: []string{"Pizza Dude", "Taco Tuesday"}},
+		"exclude-places": {given: EntryFilter{ExcludePlaces: []string{"pizza-dude"}}, expect: []string{"Fancy Spot", "Ghost Kitchen", "Old Diner", "Taco Tuesday"}},
+	}
+	for desc, tt := range tests {
+		got := entries.filter(&tt.given)
+		require.Equal(t, tt.expect, got.UniquePlaceNames(), desc)
+	}
+}
+
 func TestPopularPlace(t *testing.T) {
 	ts := []struct {
 		entries Entries
@@ -140,12 +182,12 @@ func TestNew(t *testing.T) {
 		WithEntries(
 			Entries{
 				Entry{Place: "Some Dine-In Place"},
-				Entry{Place: "Some Takeout Place", IsTakeout: true},
+				Entry{Place: "Some Takeout Place", Mode: ModeTakeout},
 			},
 		),
 		WithFilter(
 			EntryFilter{
-				OnlyTakeout: true,
+				Modes: []Mode{ModeTakeout},
 			},
 		),
 	)
@@ -153,7 +195,7 @@ func TestNew(t *testing.T) {
 	require.Equal(
 		t,
 		Entries{
-			Entry{Place: "Some Takeout Place", IsTakeout: true},
+			Entry{Place: "Some Takeout Place", Mode: ModeTakeout},
 		},
 		d.Entries(),
 	)
@@ -188,9 +230,9 @@ ratings:
 	require.Equal(
 		t,
 		Entry{
-			Place:     "Mamacitas",
-			Date:      toPTR(time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC)),
-			IsTakeout: true, Ratings: map[string]int{
+			Place: "Mamacitas",
+			Date:  toPTR(time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC)),
+			Mode:  ModeTakeout, Ratings: map[string]int{
 				"drew":  5,
 				"james": 3,
 			},
@@ -212,9 +254,9 @@ func TestLogDB(t *testing.T) {
 	require.NotNil(t, diary)
 	require.NoError(t, diary.Log(
 		Entry{
-			Place:     "Mamacitas",
-			Date:      toPTR(time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC)),
-			IsTakeout: true, Ratings: map[string]int{
+			Place: "Mamacitas",
+			Date:  toPTR(time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC)),
+			Mode:  ModeTakeout, Ratings: map[string]int{
 				"drew":  5,
 				"james": 3,
 			},
@@ -225,7 +267,7 @@ func TestLogDB(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(
 		t,
-		"- place: Mamacitas\n  date: 2024-01-15T00:00:00Z\n  takeout: true\n  ratings:\n    drew: 5\n    james: 3\n",
+		"- place: Mamacitas\n  date: 2024-01-15T00:00:00Z\n  mode: takeout\n  ratings:\n    drew: 5\n    james: 3\n",
 		string(export),
 	)
 }
@@ -234,9 +276,9 @@ func TestGet(t *testing.T) {
 	diary := New(WithDB(newTestDB(t)))
 	require.NotNil(t, diary)
 	e := Entry{
-		Place:     "Mamacitas",
-		Date:      toPTR(time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC)),
-		IsTakeout: true, Ratings: map[string]int{
+		Place: "Mamacitas",
+		Date:  toPTR(time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC)),
+		Mode:  ModeTakeout, Ratings: map[string]int{
 			"drew":  5,
 			"james": 3,
 		},
@@ -251,3 +293,56 @@ func TestGet(t *testing.T) {
 	require.EqualError(t, err, "record not found: never-exists")
 	require.Nil(t, got)
 }
+
+func TestEntryUnmarshalLegacy(t *testing.T) {
+	var got Entry
+	require.NoError(t, yaml.Unmarshal([]byte("place: Mamacitas\n"), &got))
+	require.Equal(t, ModeDineIn, got.Mode, "entries without takeout were dine-in")
+
+	require.NoError(t, json.Unmarshal([]byte(`{"Place":"Mamacitas","IsTakeout":true}`), &got))
+	require.Equal(t, Entry{Place: "Mamacitas", Mode: ModeTakeout}, got)
+
+	require.NoError(t, yaml.Unmarshal([]byte("place: Mamacitas\nmode: delivery\nplatform: doordash\n"), &got))
+	require.Equal(t, Entry{Place: "Mamacitas", Mode: ModeDelivery, Platform: "doordash"}, got)
+}
+
+func TestMigrateDB(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(EntriesBucket))
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte("/old/Mamacitas"), []byte(`{"Place":"Mamacitas","IsTakeout":true}`))
+	}))
+	d := New(WithDB(db))
+	require.NoError(t, db.View(func(tx *bolt.Tx) error {
+		got := string(tx.Bucket([]byte(EntriesBucket)).Get([]byte("/old/Mamacitas")))
+		require.Contains(t, got, `"Mode":"takeout"`)
+		require.NotContains(t, got, "IsTakeout")
+		return nil
+	}))
+	got, err := d.GetPlace("Mamacitas")
+	require.NoError(t, err)
+	require.Equal(t, "mamacitas", got.Slug)
+}
+
+func TestPlaceRegistry(t *testing.T) {
+	d := New(WithDB(newTestDB(t)))
+	require.NoError(t, d.Log(Entry{Place: "Taco Tuesday", Mode: ModeTakeout}))
+
+	got, err := d.GetPlace("taco tuesday")
+	require.NoError(t, err)
+	require.Equal(t, &Place{Name: "Taco Tuesday", Slug: "taco-tuesday"}, got)
+
+	got.Format = FormatWithModes(ModeTakeout, ModeDelivery)
+	require.NoError(t, d.SavePlace(*got))
+	require.NoError(t, d.Log(Entry{Place: "Taco Tuesday", Mode: ModeDelivery}))
+
+	places, err := d.Places()
+	require.NoError(t, err)
+	require.Equal(t, Places{{Name: "Taco Tuesday", Slug: "taco-tuesday", Format: Format{TakeOut: true, Delivery: true}}}, places)
+
+	_, err = d.GetPlace("never-exists")
+	require.EqualError(t, err, "place not found: never-exists")
+}
@@ -0,0 +1,113 @@
+package letseat
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// FirstVisits returns the date of the first visit to each place
+func (e *Entries) FirstVisits() map[string]time.Time {
+	ret := map[string]time.Time{}
+	for _, entry := range *e {
+		if entry.Date == nil {
+			continue
+		}
+		if f, ok := ret[entry.Place]; !ok || entry.Date.Before(f) {
+			ret[entry.Place] = *entry.Date
+		}
+	}
+	return ret
+}
+
+// ExplorationPeriod is how many visits in a period were to new places, and how
+// many were repeats
+type ExplorationPeriod struct {
+	Period string `yaml:"period"`
+	New    int    `yaml:"new"`
+	Repeat int    `yaml:"repeat"`
+	// Ratio is the share of visits that were to new places
+	Ratio float64 `yaml:"ratio"`
+}
+
+// String returns a human friendly version of the period, like "3 new, 12 repeat (20% new)"
+func (p ExplorationPeriod) String() string {
+	return fmt.Sprintf("%v new, %v repeat (%.0f%% new)", p.New, p.Repeat, p.Ratio*100)
+}
+
+// Exploration counts the new and repeat visits in each period, oldest first.
+// Periods are named with layout, like "2006-01" for months or "2006" for
+// years. firsts are the first visits to every place, from FirstVisits, so a
+// place isn't counted as new just because its earlier visits were filtered out
+func (e *Entries) Exploration(firsts map[string]time.Time, layout string) []ExplorationPeriod {
+	byPeriod := map[string]*ExplorationPeriod{}
+	for _, entry := range *e {
+		if entry.Date == nil {
+			continue
+		}
+		k := entry.Date.Format(layout)
+		if _, ok := byPeriod[k]; !ok {
+			byPeriod[k] = &ExplorationPeriod{Period: k}
+		}
+		if f, ok := firsts[entry.Place]; ok && f.Equal(*entry.Date) {
+			byPeriod[k].New++
+		} else {
+			byPeriod[k].Repeat++
+		}
+	}
+	ret := make([]ExplorationPeriod, 0, len(byPeriod))
+	for _, p := range byPeriod {
+		p.Ratio = round2(float64(p.New) / float64(p.New+p.Repeat))
+		ret = append(ret, *p)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Period < ret[j].Period })
+	return ret
+}
+
+// Exploration counts the new and repeat visits in each period of the filtered
+// entries. New means the first visit ever, not just the first one in the filter
+func (d Diary) Exploration(layout string) []ExplorationPeriod {
+	return d.entries.Exploration(d.unfilteredEntries.FirstVisits(), layout)
+}
+
+// GoalProgress is how a yearly goal of trying new places is going
+type GoalProgress struct {
+	Year  int `yaml:"year"`
+	Goal  int `yaml:"goal"`
+	Tried int `yaml:"tried"`
+	// Expected is how many new places should have been tried by now to stay on track
+	Expected int `yaml:"expected"`
+}
+
+// OnTrack returns true if enough new places have been tried so far this year
+func (g GoalProgress) OnTrack() bool {
+	return g.Tried >= g.Expected
+}
+
+// String returns a human friendly version of the progress, like "5 of 12 new places tried in 2024, on track"
+func (g GoalProgress) String() string {
+	ret := fmt.Sprintf("%v of %v new places tried in %v", g.Tried, g.Goal, g.Year)
+	switch {
+	case g.Tried >= g.Goal:
+		return ret + ", goal reached!"
+	case g.OnTrack():
+		return ret + ", on track"
+	default:
+		return fmt.Sprintf("%v, behind (%v by now to stay on track)", ret, g.Expected)
+	}
+}
+
+// NewPlacesGoal works out how a goal of trying goal new places in the year of
+// now is going, using every entry in the diary
+func (d Diary) NewPlacesGoal(goal int, now time.Time) GoalProgress {
+	ret := GoalProgress{Year: now.Year(), Goal: goal}
+	for _, f := range d.unfilteredEntries.FirstVisits() {
+		if f.Year() == now.Year() && !f.After(now) {
+			ret.Tried++
+		}
+	}
+	start := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, now.Location())
+	end := start.AddDate(1, 0, 0)
+	ret.Expected = int(float64(goal) * float64(now.Sub(start)) / float64(end.Sub(start)))
+	return ret
+}
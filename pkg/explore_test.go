@@ -0,0 +1,58 @@
+package letseat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExploration(t *testing.T) {
+	meal := func(place, date string) Entry {
+		return Entry{Place: place, Date: toPTR(mustTime(date))}
+	}
+	entries := Entries{
+		meal("Pizza Dude", "2023-11-03 19:00"),
+		meal("Pizza Dude", "2024-01-05 19:00"),
+		meal("Taco Tuesday", "2024-01-09 12:00"),
+		meal("Taco Tuesday", "2024-01-16 12:00"),
+		meal("BBQ Papa", "2024-02-10 19:00"),
+		meal("Mezcalito", "2024-06-01 19:00"),
+	}
+	firsts := entries.FirstVisits()
+	require.Equal(t, mustTime("2023-11-03 19:00"), firsts["Pizza Dude"])
+
+	got := entries.Exploration(firsts, "2006-01")
+	require.Equal(t, []ExplorationPeriod{
+		{Period: "2023-11", New: 1, Ratio: 1},
+		{Period: "2024-01", New: 1, Repeat: 2, Ratio: 0.33},
+		{Period: "2024-02", New: 1, Ratio: 1},
+		{Period: "2024-06", New: 1, Ratio: 1},
+	}, got)
+	require.Equal(t, "1 new, 2 repeat (33% new)", got[1].String())
+
+	// Only looking at 2024, Pizza Dude still isn't new
+	recent := entries[1:]
+	require.Equal(t, []ExplorationPeriod{
+		{Period: "2024", New: 3, Repeat: 2, Ratio: 0.6},
+	}, recent.Exploration(firsts, "2006"))
+
+	d := New(
+		WithDB(newTestDB(t)),
+		WithEntries(entries),
+		WithFilter(EntryFilter{Earliest: toPTR(mustTime("2024-01-01 00:00"))}),
+	)
+	require.Equal(t, 3, d.Exploration("2006")[0].New)
+
+	tests := map[string]struct {
+		goal   int
+		now    string
+		expect string
+	}{
+		"on track": {goal: 6, now: "2024-06-30 12:00", expect: "3 of 6 new places tried in 2024, on track"},
+		"behind":   {goal: 12, now: "2024-09-30 12:00", expect: "3 of 12 new places tried in 2024, behind (8 by now to stay on track)"},
+		"reached":  {goal: 2, now: "2024-03-01 12:00", expect: "2 of 2 new places tried in 2024, goal reached!"},
+	}
+	for desc, tt := range tests {
+		require.Equal(t, tt.expect, d.NewPlacesGoal(tt.goal, mustTime(tt.now)).String(), desc)
+	}
+}
@@ -0,0 +1,56 @@
+package letseat
+
+import "fmt"
+
+// HeatmapMetric is what a day on the heatmap is colored by
+type HeatmapMetric string
+
+const (
+	// HeatmapVisits counts the meals out on each day
+	HeatmapVisits HeatmapMetric = "visits"
+	// HeatmapCost adds up what was spent on each day, in the default currency
+	HeatmapCost HeatmapMetric = "cost"
+	// HeatmapRating averages the ratings on each day
+	HeatmapRating HeatmapMetric = "rating"
+)
+
+// ParseHeatmapMetric returns a HeatmapMetric from a string
+func ParseHeatmapMetric(s string) (HeatmapMetric, error) {
+	switch m := HeatmapMetric(s); m {
+	case HeatmapVisits, HeatmapCost, HeatmapRating:
+		return m, nil
+	}
+	return "", fmt.Errorf("unknown metric: %v, must be one of visits, cost or rating", s)
+}
+
+// Daily returns the metric for each day with a matching entry, keyed by date like "2006-01-02"
+func (e *Entries) Daily(m HeatmapMetric) map[string]float64 {
+	ret := map[string]float64{}
+	rated := map[string]int{}
+	for _, entry := range *e {
+		if entry.Date == nil {
+			continue
+		}
+		k := entry.Date.Format("2006-01-02")
+		switch m {
+		case HeatmapVisits:
+			ret[k]++
+		case HeatmapCost:
+			total, err := entry.Cost.Total()
+			if err != nil || total.IsZero() || total.currency() != DefaultCurrency {
+				continue
+			}
+			ret[k] += total.Float64()
+		case HeatmapRating:
+			if len(entry.rated()) == 0 {
+				continue
+			}
+			ret[k] += entry.averageRating()
+			rated[k]++
+		}
+	}
+	for k, n := range rated {
+		ret[k] /= float64(n)
+	}
+	return ret
+}
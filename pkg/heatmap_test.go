@@ -0,0 +1,21 @@
+package letseat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDaily(t *testing.T) {
+	entries := Entries{
+		{Place: "Pizza Dude", Date: toPTR(mustTime("2024-01-05 12:00")), Cost: Bill{Subtotal: MustParseMoney("12.50", "USD")}, Ratings: map[string]int{"drew": 4}},
+		{Place: "BBQ Papa", Date: toPTR(mustTime("2024-01-05 19:00")), Cost: Bill{Subtotal: MustParseMoney("30", "USD")}, Ratings: map[string]int{"drew": 5, "james": 3}},
+		{Place: "Le Bistro", Date: toPTR(mustTime("2024-01-06 19:00")), Cost: Bill{Subtotal: MustParseMoney("80", "EUR")}, Ratings: map[string]int{"drew": 0}},
+	}
+	require.Equal(t, map[string]float64{"2024-01-05": 2, "2024-01-06": 1}, entries.Daily(HeatmapVisits))
+	require.Equal(t, map[string]float64{"2024-01-05": 42.5}, entries.Daily(HeatmapCost), "other currencies are skipped")
+	require.Equal(t, map[string]float64{"2024-01-05": 4}, entries.Daily(HeatmapRating))
+
+	_, err := ParseHeatmapMetric("vibes")
+	require.EqualError(t, err, "unknown metric: vibes, must be one of visits, cost or rating")
+}
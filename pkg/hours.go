@@ -0,0 +1,288 @@
+package letseat
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Clock is a time of day, in minutes after midnight
+type Clock int
+
+// ParseClock reads a time of day, like "18:30", "6:30pm" or "7am". "24:00" is
+// allowed so that ranges can run right up to midnight
+func ParseClock(s string) (Clock, error) {
+	k := strings.ToUpper(strings.ReplaceAll(s, " ", ""))
+	if k == "24:00" {
+		return Clock(24 * 60), nil
+	}
+	for _, layout := range []string{"15:04", "3:04PM", "3PM"} {
+		if t, err := time.Parse(layout, k); err == nil {
+			return Clock(t.Hour()*60 + t.Minute()), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid time of day: %v", s)
+}
+
+// ClockOf returns the time of day for a given time
+func ClockOf(t time.Time) Clock {
+	return Clock(t.Hour()*60 + t.Minute())
+}
+
+// Duration returns how long after midnight the clock is
+func (c Clock) Duration() time.Duration {
+	return time.Duration(c) * time.Minute
+}
+
+// String returns the clock like "18:30"
+func (c Clock) String() string {
+	return fmt.Sprintf("%02d:%02d", int(c)/60, int(c)%60)
+}
+
+// TimeRange is a range of time during a day. If End is before Start, the
+// range runs past midnight in to the next day
+type TimeRange struct {
+	Start Clock
+	End   Clock
+}
+
+// ParseTimeRange reads a range like "11:00-14:00"
+func ParseTimeRange(s string) (TimeRange, error) {
+	start, end, ok := strings.Cut(s, "-")
+	if !ok {
+		return TimeRange{}, fmt.Errorf("time range must look like 11:00-14:00: %v", s)
+	}
+	var r TimeRange
+	var err error
+	if r.Start, err = ParseClock(start); err != nil {
+		return TimeRange{}, err
+	}
+	if r.End, err = ParseClock(end); err != nil {
+		return TimeRange{}, err
+	}
+	return r, nil
+}
+
+// String returns the range like "11:00-14:00"
+func (r TimeRange) String() string {
+	return fmt.Sprintf("%v-%v", r.Start, r.End)
+}
+
+// overnight returns true if the range runs past midnight
+func (r TimeRange) overnight() bool {
+	return r.End < r.Start
+}
+
+// MarshalText writes the range out like "11:00-14:00"
+func (r TimeRange) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText reads a range like "11:00-14:00"
+func (r *TimeRange) UnmarshalText(b []byte) error {
+	got, err := ParseTimeRange(string(b))
+	if err != nil {
+		return err
+	}
+	*r = got
+	return nil
+}
+
+// HoursException overrides the usual hours on a given date, like a holiday
+type HoursException struct {
+	Date   time.Time   `yaml:"date"`
+	Closed bool        `yaml:"closed,omitempty"`
+	Hours  []TimeRange `yaml:"hours,omitempty"`
+}
+
+// Hours are when a place is open. Weekly is keyed by the short weekday name, like "mon"
+type Hours struct {
+	Weekly     map[string][]TimeRange `yaml:"weekly,omitempty"`
+	Exceptions []HoursException       `yaml:"exceptions,omitempty"`
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// WeekdayKey returns the short name of a weekday, like "mon"
+func WeekdayKey(d time.Weekday) string {
+	return strings.ToLower(d.String()[:3])
+}
+
+// ParseWeekday reads a weekday like "fri" or "Friday"
+func ParseWeekday(s string) (time.Weekday, error) {
+	k := strings.ToLower(strings.TrimSpace(s))
+	if len(k) >= 3 {
+		if d, ok := weekdayNames[k[:3]]; ok && strings.HasPrefix(strings.ToLower(d.String()), k) {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown weekday: %v", s)
+}
+
+// ParseWeekdays reads multiple weekdays, allowing ranges like "mon-fri" and
+// shortcuts like "weekdays", "weekends" and "daily"
+func ParseWeekdays(s ...string) ([]time.Weekday, error) {
+	ret := []time.Weekday{}
+	for _, item := range s {
+		for _, part := range strings.Split(item, ",") {
+			days, err := parseWeekdayRange(part)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, days...)
+		}
+	}
+	return ret, nil
+}
+
+func parseWeekdayRange(s string) ([]time.Weekday, error) {
+	switch k := strings.ToLower(strings.TrimSpace(s)); k {
+	case "daily", "everyday", "all":
+		return []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday}, nil
+	case "weekdays":
+		return []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}, nil
+	case "weekends":
+		return []time.Weekday{time.Saturday, time.Sunday}, nil
+	}
+	first, last, isRange := strings.Cut(s, "-")
+	start, err := ParseWeekday(first)
+	if err != nil {
+		return nil, err
+	}
+	if !isRange {
+		return []time.Weekday{start}, nil
+	}
+	end, err := ParseWeekday(last)
+	if err != nil {
+		return nil, err
+	}
+	ret := []time.Weekday{start}
+	for d := start; d != end; {
+		d = (d + 1) % 7
+		ret = append(ret, d)
+	}
+	return ret, nil
+}
+
+// ParseWeeklyHours reads a weekly schedule like
+// "mon-fri 11:00-14:00,17:00-21:00; sat 10:00-22:00; sun closed"
+func ParseWeeklyHours(s string) (map[string][]TimeRange, error) {
+	ret := map[string][]TimeRange{}
+	for _, segment := range strings.Split(s, ";") {
+		segment = strings.TrimSpace(segment)
+		if segment == "" {
+			continue
+		}
+		dayS, rangesS, ok := strings.Cut(segment, " ")
+		if !ok {
+			return nil, fmt.Errorf("hours must look like 'mon-fri 11:00-14:00': %v", segment)
+		}
+		days, err := ParseWeekdays(dayS)
+		if err != nil {
+			return nil, err
+		}
+		ranges := []TimeRange{}
+		if rangesS = strings.TrimSpace(rangesS); !strings.EqualFold(rangesS, "closed") {
+			for _, item := range strings.Split(rangesS, ",") {
+				r, err := ParseTimeRange(strings.TrimSpace(item))
+				if err != nil {
+					return nil, err
+				}
+				ranges = append(ranges, r)
+			}
+		}
+		for _, d := range days {
+			ret[WeekdayKey(d)] = ranges
+		}
+	}
+	return ret, nil
+}
+
+// rangesOn returns the ranges a place is open on the day of the given time,
+// and whether or not we actually know the hours for that day
+func (h Hours) rangesOn(t time.Time) ([]TimeRange, bool) {
+	y, m, d := t.Date()
+	for _, e := range h.Exceptions {
+		ey, em, ed := e.Date.Date()
+		if ey == y && em == m && ed == d {
+			if e.Closed {
+				return nil, true
+			}
+			return e.Hours, true
+		}
+	}
+	// Days left out of the weekly hours are unknown, while "closed" days are there with no ranges
+	ranges, ok := h.Weekly[WeekdayKey(t.Weekday())]
+	return ranges, ok
+}
+
+// IsOpenAt returns true if the hours say the place is open at the given time.
+// Days we don't know anything about are assumed to be open
+func (h Hours) IsOpenAt(t time.Time) bool {
+	c := ClockOf(t)
+	today, known := h.rangesOn(t)
+	if !known {
+		return true
+	}
+	for _, r := range today {
+		if c >= r.Start && (r.overnight() || c < r.End) {
+			return true
+		}
+	}
+	// Check if last night's hours are still going
+	yesterday, _ := h.rangesOn(t.AddDate(0, 0, -1))
+	for _, r := range yesterday {
+		if r.overnight() && c < r.End {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOpenAt returns true if the place is open at a given time. If we don't know
+// the hours, we assume it's open
+func (p Place) IsOpenAt(t time.Time) bool {
+	if p.Hours == nil {
+		return true
+	}
+	return p.Hours.IsOpenAt(t)
+}
+
+// ResolveAt figures out the time meant by things like "fri 19:00", "7 pm" or
+// "2024-01-05 19:00", relative to now. Weekdays resolve to the next time that
+// day comes around, including today
+func ResolveAt(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.ParseInLocation("2006-01-02 15:04", s, now.Location()); err == nil {
+		return t, nil
+	}
+	day := now
+	clockS := s
+	// Clocks can have spaces too, like "7 pm", so they get the first shot
+	if _, err := ParseClock(s); err != nil {
+		dayS, rest, ok := strings.Cut(s, " ")
+		if !ok {
+			return time.Time{}, err
+		}
+		d, err := ParseWeekday(dayS)
+		if err != nil {
+			return time.Time{}, err
+		}
+		day = now.AddDate(0, 0, (int(d)-int(now.Weekday())+7)%7)
+		clockS = rest
+	}
+	c, err := ParseClock(clockS)
+	if err != nil {
+		return time.Time{}, err
+	}
+	y, m, d := day.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, now.Location()).Add(c.Duration()), nil
+}
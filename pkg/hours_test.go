@@ -0,0 +1,159 @@
+package letseat
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func mustTime(s string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestParseClock(t *testing.T) {
+	for given, expect := range map[string]string{
+		"18:30":   "18:30",
+		"6:30pm":  "18:30",
+		"6:30 PM": "18:30",
+		"7am":     "07:00",
+		"24:00":   "24:00",
+	} {
+		got, err := ParseClock(given)
+		require.NoError(t, err, given)
+		require.Equal(t, expect, got.String(), given)
+	}
+	_, err := ParseClock("dinner time")
+	require.EqualError(t, err, "invalid time of day: dinner time")
+}
+
+func TestParseWeekdays(t *testing.T) {
+	tests := map[string]struct {
+		given     string
+		expect    []time.Weekday
+		expectErr string
+	}{
+		"single":     {given: "fri", expect: []time.Weekday{time.Friday}},
+		"full-name":  {given: "Saturday", expect: []time.Weekday{time.Saturday}},
+		"range":      {given: "mon-wed", expect: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday}},
+		"wraps":      {given: "fri-mon", expect: []time.Weekday{time.Friday, time.Saturday, time.Sunday, time.Monday}},
+		"list":       {given: "mon,thu", expect: []time.Weekday{time.Monday, time.Thursday}},
+		"weekends":   {given: "weekends", expect: []time.Weekday{time.Saturday, time.Sunday}},
+		"bad":        {given: "funday", expectErr: "unknown weekday: funday"},
+		"bad-prefix": {given: "frx", expectErr: "unknown weekday: frx"},
+	}
+	for desc, tt := range tests {
+		got, err := ParseWeekdays(tt.given)
+		if tt.expectErr != "" {
+			require.EqualError(t, err, tt.expectErr, desc)
+			continue
+		}
+		require.NoError(t, err, desc)
+		require.Equal(t, tt.expect, got, desc)
+	}
+}
+
+func TestParseWeeklyHours(t *testing.T) {
+	got, err := ParseWeeklyHours("mon-fri 11:00-14:00,17:00-21:00; sat 10:00-02:00; sun closed")
+	require.NoError(t, err)
+	require.Equal(t, []TimeRange{{Start: 660, End: 840}, {Start: 1020, End: 1260}}, got["wed"])
+	require.Equal(t, []TimeRange{{Start: 600, End: 120}}, got["sat"])
+	require.Equal(t, []TimeRange{}, got["sun"])
+
+	_, err = ParseWeeklyHours("mon")
+	require.EqualError(t, err, "hours must look like 'mon-fri 11:00-14:00': mon")
+	_, err = ParseWeeklyHours("mon 11:00")
+	require.EqualError(t, err, "time range must look like 11:00-14:00: 11:00")
+}
+
+func TestHoursIsOpenAt(t *testing.T) {
+	weekly, err := ParseWeeklyHours("mon-fri 11:00-14:00,17:00-21:00; sat 18:00-02:00; sun closed")
+	require.NoError(t, err)
+	h := Hours{
+		Weekly: weekly,
+		Exceptions: []HoursException{
+			{Date: mustTime("2024-12-25 00:00"), Closed: true},
+			{Date: mustTime("2024-12-24 00:00"), Hours: []TimeRange{{Start: 600, End: 900}}},
+		},
+	}
+	tests := map[string]struct {
+		given  string
+		expect bool
+	}{
+		"lunch":            {given: "2024-01-05 12:00", expect: true},
+		"between":          {given: "2024-01-05 15:00", expect: false},
+		"dinner":           {given: "2024-01-05 20:59", expect: true},
+		"closing":          {given: "2024-01-05 21:00", expect: false},
+		"late-saturday":    {given: "2024-01-06 23:30", expect: true},
+		"overnight":        {given: "2024-01-07 01:30", expect: true},
+		"after-overnight":  {given: "2024-01-07 02:30", expect: false},
+		"closed-sunday":    {given: "2024-01-07 12:00", expect: false},
+		"holiday":          {given: "2024-12-25 12:00", expect: false},
+		"holiday-hours":    {given: "2024-12-24 10:30", expect: true},
+		"holiday-no-lunch": {given: "2024-12-24 17:30", expect: false},
+	}
+	for desc, tt := range tests {
+		require.Equal(t, tt.expect, h.IsOpenAt(mustTime(tt.given)), desc)
+	}
+
+	require.True(t, Place{}.IsOpenAt(mustTime("2024-01-07 04:00")), "unknown hours are open")
+	require.True(t, Hours{}.IsOpenAt(mustTime("2024-01-07 04:00")), "empty hours are open")
+
+	partial, err := ParseWeeklyHours("mon-fri 11:00-21:00")
+	require.NoError(t, err)
+	require.True(t, Hours{Weekly: partial}.IsOpenAt(mustTime("2024-01-07 04:00")), "days left out of the hours are unknown, so open")
+}
+
+func TestHoursMarshal(t *testing.T) {
+	weekly, err := ParseWeeklyHours("mon 11:00-14:00")
+	require.NoError(t, err)
+	p := Place{Name: "Foo", Hours: &Hours{Weekly: weekly}}
+
+	var fromJSON Place
+	require.NoError(t, json.Unmarshal(p.mustMarshal(), &fromJSON))
+	require.Equal(t, p.Hours, fromJSON.Hours)
+
+	out, err := yaml.Marshal(p.Hours)
+	require.NoError(t, err)
+	require.Equal(t, "weekly:\n  mon:\n  - 11:00-14:00\n", string(out))
+	var fromYAML Hours
+	require.NoError(t, yaml.Unmarshal(out, &fromYAML))
+	require.Equal(t, *p.Hours, fromYAML)
+}
+
+func TestResolveAt(t *testing.T) {
+	// A Wednesday
+	now := mustTime("2024-01-03 09:15")
+	for given, expect := range map[string]string{
+		"19:00":            "2024-01-03 19:00",
+		"fri 19:00":        "2024-01-05 19:00",
+		"wed 7pm":          "2024-01-03 19:00",
+		"7 pm":             "2024-01-03 19:00",
+		"fri 7 pm":         "2024-01-05 19:00",
+		"mon 12:00":        "2024-01-08 12:00",
+		"2024-02-01 18:30": "2024-02-01 18:30",
+	} {
+		got, err := ResolveAt(given, now)
+		require.NoError(t, err, given)
+		require.Equal(t, expect, got.Format("2006-01-02 15:04"), given)
+	}
+	_, err := ResolveAt("someday 19:00", now)
+	require.EqualError(t, err, "unknown weekday: someday")
+}
+
+func TestPlaceFilterOpenAt(t *testing.T) {
+	weekly, err := ParseWeeklyHours("mon-fri 11:00-21:00; sat-sun closed")
+	require.NoError(t, err)
+	p := &Place{Name: "Foo", Hours: &Hours{Weekly: weekly}}
+	sat := mustTime("2024-01-06 12:00")
+	fri := mustTime("2024-01-05 12:00")
+	require.False(t, PlaceFilter{OpenAt: &sat}.Match(p))
+	require.True(t, PlaceFilter{OpenAt: &fri}.Match(p))
+	require.True(t, PlaceFilter{OpenAt: &sat}.Match(nil))
+}
@@ -0,0 +1,161 @@
+package letseat
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// earthRadius is the mean radius of the earth, in kilometers
+const earthRadius = 6371.0088
+
+// Coordinates are a latitude and longitude, in degrees
+type Coordinates struct {
+	Lat  float64 `yaml:"lat"`
+	Long float64 `yaml:"long"`
+}
+
+// ParseCoordinates reads coordinates like "42.2808,-83.7430"
+func ParseCoordinates(s string) (*Coordinates, error) {
+	lat, long, ok := strings.Cut(s, ",")
+	if !ok {
+		return nil, fmt.Errorf("coordinates must look like LAT,LONG: %v", s)
+	}
+	var c Coordinates
+	var err error
+	if c.Lat, err = strconv.ParseFloat(strings.TrimSpace(lat), 64); err != nil {
+		return nil, fmt.Errorf("invalid latitude: %v", lat)
+	}
+	if c.Long, err = strconv.ParseFloat(strings.TrimSpace(long), 64); err != nil {
+		return nil, fmt.Errorf("invalid longitude: %v", long)
+	}
+	if c.Lat < -90 || c.Lat > 90 {
+		return nil, fmt.Errorf("latitude must be between -90 and 90: %v", c.Lat)
+	}
+	if c.Long < -180 || c.Long > 180 {
+		return nil, fmt.Errorf("longitude must be between -180 and 180: %v", c.Long)
+	}
+	return &c, nil
+}
+
+// String satisfies the Stringer interface
+func (c Coordinates) String() string {
+	return fmt.Sprintf("%v,%v", c.Lat, c.Long)
+}
+
+// DistanceTo returns the great circle distance to some other coordinates,
+// using the haversine formula
+func (c Coordinates) DistanceTo(o Coordinates) Distance {
+	rad := func(d float64) float64 { return d * math.Pi / 180 }
+	dLat := rad(o.Lat - c.Lat)
+	dLong := rad(o.Long - c.Long)
+	a := math.Pow(math.Sin(dLat/2), 2) + math.Cos(rad(c.Lat))*math.Cos(rad(o.Lat))*math.Pow(math.Sin(dLong/2), 2)
+	return Distance(2 * earthRadius * math.Asin(math.Sqrt(a)))
+}
+
+// Distance is a distance in kilometers
+type Distance float64
+
+var distanceUnits = map[string]float64{
+	"":   1,
+	"km": 1,
+	"m":  0.001,
+	"mi": 1.609344,
+}
+
+// ParseDistance reads distances like "5km", "800m" or "3mi". Plain numbers are kilometers
+func ParseDistance(s string) (Distance, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	num, unit := s, ""
+	if i >= 0 {
+		num, unit = s[:i], strings.TrimSpace(s[i:])
+	}
+	mult, ok := distanceUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("unknown distance unit %q in %q", unit, s)
+	}
+	v, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid distance: %q", s)
+	}
+	return Distance(v * mult), nil
+}
+
+// String returns the distance in a human friendly way
+func (d Distance) String() string {
+	if d < 1 {
+		return fmt.Sprintf("%.0fm", float64(d)*1000)
+	}
+	return fmt.Sprintf("%.1fkm", float64(d))
+}
+
+// DistanceFrom returns how far the place is from some origin. Returns false if
+// we don't know where the place is
+func (p Place) DistanceFrom(o Coordinates) (Distance, bool) {
+	if p.Location == nil {
+		return 0, false
+	}
+	return o.DistanceTo(*p.Location), true
+}
+
+// PlaceFilter defines how to filter places when picking somewhere to go
+type PlaceFilter struct {
+	Origin   *Coordinates
+	Within   Distance
+	OpenAt   *time.Time
+	ActiveOn *time.Time
+	Tiers    []int
+}
+
+// Match returns true if the place makes it through the filter
+func (f PlaceFilter) Match(p *Place) bool {
+	if f.Within > 0 && f.Origin != nil {
+		if p == nil {
+			return false
+		}
+		d, ok := p.DistanceFrom(*f.Origin)
+		if !ok || d > f.Within {
+			return false
+		}
+	}
+	if len(f.Tiers) > 0 && !matchTier(p, f.Tiers) {
+		return false
+	}
+	if f.ActiveOn != nil && p != nil && !p.IsActiveOn(*f.ActiveOn) {
+		return false
+	}
+	if f.OpenAt != nil && p != nil && !p.IsOpenAt(*f.OpenAt) {
+		return false
+	}
+	return true
+}
+
+// MatchDetail returns true if the place detail makes it through the filter.
+// Brands match if any of their locations do
+func (f PlaceFilter) MatchDetail(d PlaceDetail) bool {
+	if len(d.Locations) == 0 {
+		return f.Match(d.Place)
+	}
+	for _, l := range d.Locations {
+		if f.Match(l) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns the place details that match the filter
+func (p PlaceDetails) Filter(f PlaceFilter) PlaceDetails {
+	ret := PlaceDetails{}
+	for _, item := range p {
+		if f.MatchDetail(item) {
+			ret = append(ret, item)
+		}
+	}
+	return ret
+}
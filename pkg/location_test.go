@@ -0,0 +1,64 @@
+package letseat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDistanceTo(t *testing.T) {
+	annArbor := Coordinates{Lat: 42.2808, Long: -83.7430}
+	detroit := Coordinates{Lat: 42.3314, Long: -83.0458}
+	require.InDelta(t, 57.5, float64(annArbor.DistanceTo(detroit)), 0.5)
+	require.InDelta(t, 0, float64(annArbor.DistanceTo(annArbor)), 0.0001)
+}
+
+func TestParseCoordinates(t *testing.T) {
+	got, err := ParseCoordinates("42.2808, -83.7430")
+	require.NoError(t, err)
+	require.Equal(t, &Coordinates{Lat: 42.2808, Long: -83.7430}, got)
+
+	_, err = ParseCoordinates("42.2808")
+	require.EqualError(t, err, "coordinates must look like LAT,LONG: 42.2808")
+	_, err = ParseCoordinates("142.2808,1")
+	require.EqualError(t, err, "latitude must be between -90 and 90: 142.2808")
+}
+
+func TestParseDistance(t *testing.T) {
+	tests := map[string]struct {
+		given     string
+		expect    Distance
+		expectErr string
+	}{
+		"km":       {given: "5km", expect: 5},
+		"plain":    {given: "2.5", expect: 2.5},
+		"meters":   {given: "800m", expect: 0.8},
+		"miles":    {given: "1 mi", expect: 1.609344},
+		"parsecs":  {given: "3pc", expectErr: `unknown distance unit "pc" in "3pc"`},
+		"no value": {given: "km", expectErr: `invalid distance: "km"`},
+	}
+	for desc, tt := range tests {
+		got, err := ParseDistance(tt.given)
+		if tt.expectErr != "" {
+			require.EqualError(t, err, tt.expectErr, desc)
+			continue
+		}
+		require.NoError(t, err, desc)
+		require.InDelta(t, float64(tt.expect), float64(got), 0.000001, desc)
+	}
+	require.Equal(t, "800m", Distance(0.8).String())
+	require.Equal(t, "12.3km", Distance(12.34).String())
+}
+
+func TestPlaceFilterWithin(t *testing.T) {
+	home := Coordinates{Lat: 42.2808, Long: -83.7430}
+	near := Place{Name: "Near", Location: &Coordinates{Lat: 42.2800, Long: -83.7500}}
+	far := Place{Name: "Far", Location: &Coordinates{Lat: 42.3314, Long: -83.0458}}
+	unknown := Place{Name: "Unknown"}
+
+	details := PlaceDetails{{Name: "Near", Place: &near}, {Name: "Far", Place: &far}, {Name: "Unknown", Place: &unknown}}
+	require.Len(t, details.Filter(PlaceFilter{}), 3)
+	got := details.Filter(PlaceFilter{Origin: &home, Within: 5})
+	require.Len(t, got, 1)
+	require.Equal(t, "Near", got[0].Name)
+}
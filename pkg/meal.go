@@ -0,0 +1,104 @@
+package letseat
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// MealType is the kind of meal, like lunch or dinner
+type MealType string
+
+const (
+	// MealBreakfast is the first meal of the day, brunch included
+	MealBreakfast MealType = "breakfast"
+	// MealLunch is the midday meal
+	MealLunch MealType = "lunch"
+	// MealDinner is the evening meal
+	MealDinner MealType = "dinner"
+	// MealLateNight is anything after the bars let out
+	MealLateNight MealType = "late-night"
+	// MealSnack is something small between meals
+	MealSnack MealType = "snack"
+)
+
+// AllMealTypes is every known meal type, in the order they happen during the day
+var AllMealTypes = []MealType{MealBreakfast, MealLunch, MealDinner, MealLateNight, MealSnack}
+
+// String satisfies the Stringer interface
+func (m MealType) String() string {
+	if m == "" {
+		return "unknown"
+	}
+	return string(m)
+}
+
+// ParseMealType returns a MealType from a string
+func ParseMealType(s string) (MealType, error) {
+	k := strings.NewReplacer("-", "", "_", "", " ", "").Replace(strings.ToLower(s))
+	for _, m := range AllMealTypes {
+		if strings.ReplaceAll(string(m), "-", "") == k {
+			return m, nil
+		}
+	}
+	if k == "brunch" {
+		return MealBreakfast, nil
+	}
+	return "", fmt.Errorf("unknown meal type: %v", s)
+}
+
+// ParseMealTypes parses multiple meal types at once
+func ParseMealTypes(s []string) ([]MealType, error) {
+	ret := make([]MealType, len(s))
+	for idx, item := range s {
+		m, err := ParseMealType(item)
+		if err != nil {
+			return nil, err
+		}
+		ret[idx] = m
+	}
+	return ret, nil
+}
+
+// MealTypeAt guesses which meal you'd be eating at a given time of day
+func MealTypeAt(t time.Time) MealType {
+	switch h := t.Hour(); {
+	case h >= 5 && h < 11:
+		return MealBreakfast
+	case h >= 11 && h < 16:
+		return MealLunch
+	case h >= 16 && h < 22:
+		return MealDinner
+	default:
+		return MealLateNight
+	}
+}
+
+// MealSummary is how many times, and how well, you ate a given meal type
+type MealSummary struct {
+	Meal          MealType
+	Visits        int
+	AverageRating float64
+}
+
+// MealBreakdown summarizes the entries by meal type. Entries without a known
+// meal type are summarized at the end
+func (e *Entries) MealBreakdown() []MealSummary {
+	byMeal := map[MealType]Entries{}
+	for _, entry := range *e {
+		m := entry.MealType()
+		byMeal[m] = append(byMeal[m], entry)
+	}
+	ret := []MealSummary{}
+	for _, m := range append(slices.Clone(AllMealTypes), "") {
+		if entries, ok := byMeal[m]; ok {
+			ret = append(ret, MealSummary{
+				Meal:          m,
+				Visits:        len(entries),
+				AverageRating: entries.averageRating(),
+			})
+		}
+	}
+	return ret
+}
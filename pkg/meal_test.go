@@ -0,0 +1,55 @@
+package letseat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMealType(t *testing.T) {
+	got, err := ParseMealType("Late Night")
+	require.NoError(t, err)
+	require.Equal(t, MealLateNight, got)
+
+	got, err = ParseMealType("brunch")
+	require.NoError(t, err)
+	require.Equal(t, MealBreakfast, got)
+
+	_, err = ParseMealType("elevenses")
+	require.EqualError(t, err, "unknown meal type: elevenses")
+}
+
+func TestMealTypeAt(t *testing.T) {
+	at := func(h int) time.Time { return time.Date(2024, 1, 6, h, 30, 0, 0, time.UTC) }
+	require.Equal(t, MealBreakfast, MealTypeAt(at(9)))
+	require.Equal(t, MealLunch, MealTypeAt(at(12)))
+	require.Equal(t, MealDinner, MealTypeAt(at(19)))
+	require.Equal(t, MealLateNight, MealTypeAt(at(23)))
+	require.Equal(t, MealLateNight, MealTypeAt(at(1)))
+}
+
+func TestEntryMealType(t *testing.T) {
+	require.Equal(t, MealType(""), Entry{Date: toPTR(time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC))}.MealType(), "date only")
+	require.Equal(t, MealDinner, Entry{Date: toPTR(time.Date(2024, 1, 6, 18, 15, 0, 0, time.UTC))}.MealType())
+	require.Equal(t, MealSnack, Entry{Meal: MealSnack, Date: toPTR(time.Date(2024, 1, 6, 18, 15, 0, 0, time.UTC))}.MealType())
+}
+
+func TestMealBreakdown(t *testing.T) {
+	e := Entries{
+		{Place: "A", Meal: MealDinner, Ratings: map[string]int{"a": 4}},
+		{Place: "B", Meal: MealBreakfast, Ratings: map[string]int{"a": 5}},
+		{Place: "C", Meal: MealDinner, Ratings: map[string]int{"a": 2}},
+		{Place: "D"},
+	}
+	require.Equal(
+		t,
+		[]MealSummary{
+			{Meal: MealBreakfast, Visits: 1, AverageRating: 5},
+			{Meal: MealDinner, Visits: 2, AverageRating: 3},
+			{Meal: "", Visits: 1, AverageRating: 0},
+		},
+		e.MealBreakdown(),
+	)
+	require.Len(t, e.filter(&EntryFilter{Meals: []MealType{MealDinner}}), 2)
+}
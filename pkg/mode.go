@@ -0,0 +1,54 @@
+package letseat
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Mode is the way a meal was served to you, like dine-in or delivery
+type Mode string
+
+const (
+	// ModeDineIn is eating at the restaurant
+	ModeDineIn Mode = "dine-in"
+	// ModeTakeout is picking up food and eating it somewhere else
+	ModeTakeout Mode = "takeout"
+	// ModeDelivery is having the food brought to you
+	ModeDelivery Mode = "delivery"
+	// ModeDriveThru is ordering from the car
+	ModeDriveThru Mode = "drive-thru"
+	// ModeFoodTruck is eating from a food truck
+	ModeFoodTruck Mode = "food-truck"
+)
+
+// AllModes is every known service mode, in the order they should be offered
+var AllModes = []Mode{ModeDineIn, ModeTakeout, ModeDelivery, ModeDriveThru, ModeFoodTruck}
+
+// String satisfies the Stringer interface
+func (m Mode) String() string {
+	return string(m)
+}
+
+// ParseMode returns a Mode from a string, being forgiving about dashes and case
+func ParseMode(s string) (Mode, error) {
+	k := strings.NewReplacer("-", "", "_", "", " ", "").Replace(strings.ToLower(s))
+	for _, m := range AllModes {
+		if strings.ReplaceAll(string(m), "-", "") == k {
+			return m, nil
+		}
+	}
+	return "", fmt.Errorf("unknown mode: %v", s)
+}
+
+// ParseModes parses multiple modes at once
+func ParseModes(s []string) ([]Mode, error) {
+	ret := make([]Mode, len(s))
+	for idx, item := range s {
+		m, err := ParseMode(item)
+		if err != nil {
+			return nil, err
+		}
+		ret[idx] = m
+	}
+	return ret, nil
+}
@@ -0,0 +1,31 @@
+package letseat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMode(t *testing.T) {
+	tests := map[string]struct {
+		given     string
+		expect    Mode
+		expectErr string
+	}{
+		"dine-in":       {given: "dine-in", expect: ModeDineIn},
+		"no-dash":       {given: "dinein", expect: ModeDineIn},
+		"take out":      {given: "Take Out", expect: ModeTakeout},
+		"drive-thru":    {given: "drive_thru", expect: ModeDriveThru},
+		"food truck":    {given: "foodtruck", expect: ModeFoodTruck},
+		"unknown modes": {given: "teleport", expectErr: "unknown mode: teleport"},
+	}
+	for desc, tt := range tests {
+		got, err := ParseMode(tt.given)
+		if tt.expectErr != "" {
+			require.EqualError(t, err, tt.expectErr, desc)
+			continue
+		}
+		require.NoError(t, err, desc)
+		require.Equal(t, tt.expect, got, desc)
+	}
+}
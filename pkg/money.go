@@ -0,0 +1,351 @@
+package letseat
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is used for any amount that doesn't say what currency it is in
+var DefaultCurrency = "USD"
+
+// currencyExponents are the number of minor units for currencies that don't use cents
+var currencyExponents = map[string]int{
+	"JPY": 0,
+	"KRW": 0,
+	"ISK": 0,
+	"BHD": 3,
+	"KWD": 3,
+}
+
+var currencySymbols = map[string]string{
+	"$": "USD",
+	"€": "EUR",
+	"£": "GBP",
+	"¥": "JPY",
+}
+
+// Money is an amount of a currency, stored in minor units (like cents) so that
+// adding things up never drifts the way floats do
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// NewMoney returns a new Money from an amount in minor units
+func NewMoney(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: strings.ToUpper(currency)}
+}
+
+func exponent(currency string) int {
+	if e, ok := currencyExponents[currency]; ok {
+		return e
+	}
+	return 2
+}
+
+func pow10(n int) int64 {
+	ret := int64(1)
+	for i := 0; i < n; i++ {
+		ret *= 10
+	}
+	return ret
+}
+
+// ParseMoney reads things like "23.45", "$23.45" or "23.45 EUR" in to Money. If
+// the string doesn't include a currency, the given currency is used
+func ParseMoney(s, currency string) (Money, error) {
+	orig := s
+	s = strings.ReplaceAll(strings.TrimSpace(s), ",", "")
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	for sym, code := range currencySymbols {
+		if strings.HasPrefix(s, sym) {
+			s = strings.TrimSpace(strings.TrimPrefix(s, sym))
+			currency = code
+		}
+	}
+	if fields := strings.Fields(s); len(fields) == 2 {
+		switch {
+		case isCurrencyCode(fields[0]):
+			currency, s = fields[0], fields[1]
+		case isCurrencyCode(fields[1]):
+			s, currency = fields[0], fields[1]
+		}
+	}
+	currency = strings.ToUpper(currency)
+	if s == "" {
+		return Money{}, fmt.Errorf("invalid amount: %q", orig)
+	}
+
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	whole, frac, _ := strings.Cut(s, ".")
+	exp := exponent(currency)
+	if len(frac) > exp {
+		return Money{}, fmt.Errorf("too many decimal places for %v: %q", currency, orig)
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	if !isDigits(whole) || (frac != "" && !isDigits(frac)) {
+		return Money{}, fmt.Errorf("invalid amount: %q", orig)
+	}
+	w, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("invalid amount: %q", orig)
+	}
+	var f int64
+	if frac != "" {
+		if f, err = strconv.ParseInt(frac, 10, 64); err != nil {
+			return Money{}, fmt.Errorf("invalid amount: %q", orig)
+		}
+		f *= pow10(exp - len(frac))
+	}
+	amount := w*pow10(exp) + f
+	if neg {
+		amount = -amount
+	}
+	return NewMoney(amount, currency), nil
+}
+
+// MustParseMoney parses money or panics
+func MustParseMoney(s, currency string) Money {
+	m, err := ParseMoney(s, currency)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isCurrencyCode(s string) bool {
+	if len(s) != 3 {
+		return false
+	}
+	for _, c := range s {
+		if (c < 'A' || c > 'Z') && (c < 'a' || c > 'z') {
+			return false
+		}
+	}
+	return true
+}
+
+// IsZero returns true if there is no money
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// Decimal returns just the number part of the money, like "23.45"
+func (m Money) Decimal() string {
+	exp := exponent(m.currency())
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	if exp == 0 {
+		return fmt.Sprintf("%v%d", sign, amount)
+	}
+	return fmt.Sprintf("%v%d.%0*d", sign, amount/pow10(exp), exp, amount%pow10(exp))
+}
+
+// String returns a human friendly version of the money, like "$23.45"
+func (m Money) String() string {
+	for sym, code := range currencySymbols {
+		if code == m.currency() {
+			if m.Amount < 0 {
+				return "-" + sym + NewMoney(-m.Amount, code).Decimal()
+			}
+			return sym + m.Decimal()
+		}
+	}
+	return fmt.Sprintf("%v %v", m.Decimal(), m.currency())
+}
+
+// Float64 returns the money in major units. Only use this for statistics, never for sums
+func (m Money) Float64() float64 {
+	return float64(m.Amount) / float64(pow10(exponent(m.currency())))
+}
+
+func (m Money) currency() string {
+	if m.Currency == "" {
+		return DefaultCurrency
+	}
+	return m.Currency
+}
+
+// Add returns the sum of two amounts of money in the same currency
+func (m Money) Add(o Money) (Money, error) {
+	switch {
+	case m.IsZero() && m.Currency == "":
+		return o, nil
+	case o.IsZero() && o.Currency == "":
+		return m, nil
+	case m.currency() != o.currency():
+		return Money{}, fmt.Errorf("cannot add %v to %v", o.currency(), m.currency())
+	}
+	return NewMoney(m.Amount+o.Amount, m.currency()), nil
+}
+
+// Div splits money in to n parts, rounding to the nearest minor unit
+func (m Money) Div(n int) Money {
+	if n == 0 {
+		return m
+	}
+	half := int64(n) / 2
+	if m.Amount < 0 {
+		half = -half
+	}
+	return NewMoney((m.Amount+half)/int64(n), m.currency())
+}
+
+// MarshalYAML writes money out like "23.45 USD"
+func (m Money) MarshalYAML() (interface{}, error) {
+	return fmt.Sprintf("%v %v", m.Decimal(), m.currency()), nil
+}
+
+// UnmarshalYAML reads money from anything ParseMoney understands
+func (m *Money) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	got, err := ParseMoney(s, "")
+	if err != nil {
+		return err
+	}
+	*m = got
+	return nil
+}
+
+// Totals are sums of money, one for each currency
+type Totals map[string]Money
+
+// Add adds money to the totals
+func (t Totals) Add(m Money) {
+	if m.IsZero() {
+		return
+	}
+	c := m.currency()
+	t[c] = NewMoney(t[c].Amount+m.Amount, c)
+}
+
+// Merge adds all of the other totals in to this one
+func (t Totals) Merge(o Totals) {
+	for _, m := range o {
+		t.Add(m)
+	}
+}
+
+// Primary returns the total in the default currency
+func (t Totals) Primary() Money {
+	if m, ok := t[DefaultCurrency]; ok {
+		return m
+	}
+	return NewMoney(0, DefaultCurrency)
+}
+
+// String returns all the totals, like "$23.45, 10.00 EUR"
+func (t Totals) String() string {
+	if len(t) == 0 {
+		return NewMoney(0, DefaultCurrency).String()
+	}
+	keys := make([]string, 0, len(t))
+	for k := range t {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	ret := make([]string, len(keys))
+	for idx, k := range keys {
+		ret[idx] = t[k].String()
+	}
+	return strings.Join(ret, ", ")
+}
+
+// Bill is what a meal cost, broken down in to the pieces you pay for
+type Bill struct {
+	Subtotal    Money `yaml:"subtotal,omitempty"`
+	Tax         Money `yaml:"tax,omitempty"`
+	Tip         Money `yaml:"tip,omitempty"`
+	DeliveryFee Money `yaml:"delivery_fee,omitempty"`
+}
+
+// IsZero returns true if nothing was recorded on the bill
+func (b Bill) IsZero() bool {
+	return b.Subtotal.IsZero() && b.Tax.IsZero() && b.Tip.IsZero() && b.DeliveryFee.IsZero()
+}
+
+// Totals returns everything on the bill, summed up by currency
+func (b Bill) Totals() Totals {
+	t := Totals{}
+	for _, m := range []Money{b.Subtotal, b.Tax, b.Tip, b.DeliveryFee} {
+		t.Add(m)
+	}
+	return t
+}
+
+// Total returns the whole bill. Returns an error if the bill mixes currencies
+func (b Bill) Total() (Money, error) {
+	t := b.Totals()
+	switch len(t) {
+	case 0:
+		return NewMoney(0, DefaultCurrency), nil
+	case 1:
+		for _, m := range t {
+			return m, nil
+		}
+	}
+	return Money{}, errors.New("bill uses more than one currency")
+}
+
+// billAlias lets us unmarshal a Bill without recursing in to the custom unmarshalers
+type billAlias Bill
+
+// UnmarshalYAML reads a bill. Older diaries stored cost as a plain number, which
+// is treated as the subtotal
+func (b *Bill) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var a billAlias
+	if err := unmarshal(&a); err == nil {
+		*b = Bill(a)
+		return nil
+	}
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	m, err := ParseMoney(s, "")
+	if err != nil {
+		return err
+	}
+	*b = Bill{Subtotal: m}
+	return nil
+}
+
+// UnmarshalJSON reads a bill. Older databases stored cost as whole dollars
+func (b *Bill) UnmarshalJSON(data []byte) error {
+	var legacy int64
+	if err := json.Unmarshal(data, &legacy); err == nil {
+		*b = Bill{Subtotal: NewMoney(legacy*pow10(exponent(DefaultCurrency)), DefaultCurrency)}
+		return nil
+	}
+	var a billAlias
+	if err := json.Unmarshal(data, &a); err != nil {
+		return err
+	}
+	*b = Bill(a)
+	return nil
+}
@@ -0,0 +1,100 @@
+package letseat
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := map[string]struct {
+		given     string
+		currency  string
+		expect    Money
+		expectErr string
+	}{
+		"plain":          {given: "23.45", expect: Money{Amount: 2345, Currency: "USD"}},
+		"dollar sign":    {given: "$23.45", expect: Money{Amount: 2345, Currency: "USD"}},
+		"whole":          {given: "23", expect: Money{Amount: 2300, Currency: "USD"}},
+		"one decimal":    {given: "23.5", expect: Money{Amount: 2350, Currency: "USD"}},
+		"thousands":      {given: "1,023.45", expect: Money{Amount: 102345, Currency: "USD"}},
+		"code suffix":    {given: "23.45 eur", expect: Money{Amount: 2345, Currency: "EUR"}},
+		"code prefix":    {given: "GBP 3", expect: Money{Amount: 300, Currency: "GBP"}},
+		"given currency": {given: "12", currency: "cad", expect: Money{Amount: 1200, Currency: "CAD"}},
+		"no cents":       {given: "¥1200", expect: Money{Amount: 1200, Currency: "JPY"}},
+		"too precise":    {given: "1.234", expectErr: `too many decimal places for USD: "1.234"`},
+		"garbage":        {given: "lots", expectErr: `invalid amount: "lots"`},
+		"empty":          {given: "", expectErr: `invalid amount: ""`},
+		"signed cents":   {given: "1.+5", expectErr: `invalid amount: "1.+5"`},
+	}
+	for desc, tt := range tests {
+		got, err := ParseMoney(tt.given, tt.currency)
+		if tt.expectErr != "" {
+			require.EqualError(t, err, tt.expectErr, desc)
+			continue
+		}
+		require.NoError(t, err, desc)
+		require.Equal(t, tt.expect, got, desc)
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	require.Equal(t, "$23.05", NewMoney(2305, "USD").String())
+	require.Equal(t, "-$0.50", NewMoney(-50, "USD").String())
+	require.Equal(t, "23.05 CAD", NewMoney(2305, "CAD").String())
+	require.Equal(t, "¥1200", NewMoney(1200, "JPY").String())
+}
+
+func TestMoneyMath(t *testing.T) {
+	got, err := MustParseMoney("0.10", "").Add(MustParseMoney("0.20", ""))
+	require.NoError(t, err)
+	require.Equal(t, MustParseMoney("0.30", ""), got, "no float drift here")
+
+	_, err = MustParseMoney("1", "USD").Add(MustParseMoney("1", "EUR"))
+	require.EqualError(t, err, "cannot add EUR to USD")
+
+	require.Equal(t, NewMoney(333, "USD"), NewMoney(1000, "USD").Div(3))
+	require.Equal(t, NewMoney(334, "USD"), NewMoney(1001, "USD").Div(3))
+}
+
+func TestBill(t *testing.T) {
+	b := Bill{
+		Subtotal: MustParseMoney("20.00", ""),
+		Tax:      MustParseMoney("1.65", ""),
+		Tip:      MustParseMoney("4.00", ""),
+	}
+	got, err := b.Total()
+	require.NoError(t, err)
+	require.Equal(t, "$25.65", got.String())
+
+	b.DeliveryFee = MustParseMoney("2 EUR", "")
+	_, err = b.Total()
+	require.EqualError(t, err, "bill uses more than one currency")
+	require.Equal(t, "€2.00, $25.65", b.Totals().String())
+}
+
+func TestBillUnmarshal(t *testing.T) {
+	var got Entry
+	require.NoError(t, yaml.Unmarshal([]byte("place: Mamacitas\ncost: 23\n"), &got))
+	require.Equal(t, Bill{Subtotal: NewMoney(2300, "USD")}, got.Cost, "older diaries used whole dollars")
+
+	require.NoError(t, yaml.Unmarshal([]byte("place: Mamacitas\ncost:\n  subtotal: 20.10\n  tip: 4 USD\n"), &got))
+	require.Equal(t, Bill{Subtotal: NewMoney(2010, "USD"), Tip: NewMoney(400, "USD")}, got.Cost)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"Place":"Mamacitas","Cost":15}`), &got))
+	require.Equal(t, Bill{Subtotal: NewMoney(1500, "USD")}, got.Cost)
+
+	out, err := yaml.Marshal(Entry{Place: "Mamacitas", Mode: ModeDineIn, Cost: Bill{Subtotal: NewMoney(2010, "USD")}})
+	require.NoError(t, err)
+	require.Equal(t, "place: Mamacitas\ncost:\n  subtotal: 20.10 USD\ndate: null\nmode: dine-in\n", string(out))
+}
+
+func TestTotalCost(t *testing.T) {
+	e := Entries{}
+	for i := 0; i < 10; i++ {
+		e = append(e, Entry{Cost: Bill{Subtotal: MustParseMoney("0.10", "")}})
+	}
+	require.Equal(t, "$1.00", e.TotalCost().String())
+}
@@ -0,0 +1,154 @@
+package letseat
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/montanaflynn/stats"
+)
+
+// Normalization is how ratings are adjusted for harsh or generous raters
+type Normalization string
+
+const (
+	// NormalizeNone uses ratings as they were given
+	NormalizeNone Normalization = "none"
+	// NormalizeMean shifts each person's ratings so their average matches everyone's
+	NormalizeMean Normalization = "mean"
+	// NormalizeZScore shifts and stretches each person's ratings so their
+	// average and spread match everyone's
+	NormalizeZScore Normalization = "zscore"
+)
+
+// ratings run from 1 to 5 stars, and normalized ratings are kept in that range
+const (
+	minStars = 1
+	maxStars = 5
+)
+
+// ParseNormalization returns a Normalization from a string. Empty means none
+func ParseNormalization(s string) (Normalization, error) {
+	switch Normalization(s) {
+	case "", NormalizeNone:
+		return NormalizeNone, nil
+	case NormalizeMean, NormalizeZScore:
+		return Normalization(s), nil
+	}
+	return "", fmt.Errorf("unknown normalization: %v, must be one of none, mean or zscore", s)
+}
+
+// WithNormalization adjusts the filtered entries for harsh or generous raters
+func WithNormalization(n Normalization) func(*Diary) {
+	return func(d *Diary) {
+		d.normalization = n
+	}
+}
+
+// RaterBias is how someone's ratings compare to everyone's
+type RaterBias struct {
+	Person  string  `yaml:"person"`
+	Ratings int     `yaml:"ratings"`
+	Mean    float64 `yaml:"mean"`
+	StdDev  float64 `yaml:"std_dev"`
+	// Bias is how many stars above (or below) everyone's average they rate
+	Bias float64 `yaml:"bias"`
+}
+
+// raterStats are the raw numbers behind a RaterBias
+type raterStats struct {
+	n          int
+	mean, sdev float64
+}
+
+func ratingStats(r []float64) raterStats {
+	ret := raterStats{n: len(r)}
+	if len(r) == 0 {
+		return ret
+	}
+	ret.mean, _ = stats.Mean(r)
+	ret.sdev, _ = stats.StandardDeviationPopulation(r)
+	return ret
+}
+
+// raterStats returns the stats for everyone's ratings, and for each rater. A 0
+// is "No Rating", so it isn't counted
+func (e *Entries) raterStats() (raterStats, map[string]raterStats) {
+	all := []float64{}
+	byRater := map[string][]float64{}
+	for _, entry := range *e {
+		for name, r := range entry.rated() {
+			all = append(all, float64(r))
+			byRater[name] = append(byRater[name], float64(r))
+		}
+	}
+	ret := map[string]raterStats{}
+	for name, r := range byRater {
+		ret[name] = ratingStats(r)
+	}
+	return ratingStats(all), ret
+}
+
+// RaterBiases returns how each person's ratings compare to everyone's,
+// most generous first
+func (e *Entries) RaterBiases() []RaterBias {
+	all, raters := e.raterStats()
+	ret := make([]RaterBias, 0, len(raters))
+	for name, s := range raters {
+		ret = append(ret, RaterBias{
+			Person:  name,
+			Ratings: s.n,
+			Mean:    round2(s.mean),
+			StdDev:  round2(s.sdev),
+			Bias:    round2(s.mean - all.mean),
+		})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Bias != ret[j].Bias {
+			return ret[i].Bias > ret[j].Bias
+		}
+		return ret[i].Person < ret[j].Person
+	})
+	return ret
+}
+
+// Normalized returns a copy of the entries, with every rating adjusted for how
+// harsh or generous the person giving it is
+func (e *Entries) Normalized(n Normalization) Entries {
+	return e.normalizeWith(n, *e)
+}
+
+// normalizeWith adjusts the ratings using how people rate across basis, so a
+// filtered set of entries can be judged against everything
+func (e *Entries) normalizeWith(n Normalization, basis Entries) Entries {
+	if n == NormalizeNone || n == "" {
+		return *e
+	}
+	all, raters := basis.raterStats()
+	ret := make(Entries, len(*e))
+	for idx, entry := range *e {
+		// A 0 is "No Rating", so it's left out of adjusted and stays unrated
+		entry.adjusted = make(map[string]float64, len(entry.Ratings))
+		for name, r := range entry.rated() {
+			entry.adjusted[name] = normalizeRating(float64(r), n, raters[name], all)
+		}
+		ret[idx] = entry
+	}
+	return ret
+}
+
+// normalizeRating moves a single rating from the rater's scale to everyone's.
+// Raters that always give the same rating can only be shifted, not stretched
+func normalizeRating(r float64, n Normalization, rater, all raterStats) float64 {
+	if rater.n == 0 {
+		return r
+	}
+	var ret float64
+	switch {
+	case n == NormalizeZScore && rater.sdev > 0:
+		ret = all.mean + (r-rater.mean)/rater.sdev*all.sdev
+	default:
+		ret = r - rater.mean + all.mean
+	}
+	return math.Max(minStars, math.Min(maxStars, ret))
+}
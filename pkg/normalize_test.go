@@ -0,0 +1,90 @@
+package letseat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNormalization(t *testing.T) {
+	tests := map[string]struct {
+		given     string
+		expect    Normalization
+		expectErr string
+	}{
+		"empty":   {given: "", expect: NormalizeNone},
+		"none":    {given: "none", expect: NormalizeNone},
+		"mean":    {given: "mean", expect: NormalizeMean},
+		"zscore":  {given: "zscore", expect: NormalizeZScore},
+		"unknown": {given: "vibes", expectErr: "unknown normalization: vibes, must be one of none, mean or zscore"},
+	}
+	for desc, tt := range tests {
+		got, err := ParseNormalization(tt.given)
+		if tt.expectErr != "" {
+			require.EqualError(t, err, tt.expectErr, desc)
+			continue
+		}
+		require.NoError(t, err, desc)
+		require.Equal(t, tt.expect, got, desc)
+	}
+}
+
+func TestNormalized(t *testing.T) {
+	// james rates everything a star higher than drew
+	entries := Entries{
+		{Place: "Pizza Dude", Ratings: map[string]int{"drew": 2, "james": 5}},
+		{Place: "Taco Tuesday", Ratings: map[string]int{"drew": 4}},
+		{Place: "BBQ Papa", Ratings: map[string]int{"james": 3}},
+	}
+	require.Equal(t, []RaterBias{
+		{Person: "james", Ratings: 2, Mean: 4, StdDev: 1, Bias: 0.5},
+		{Person: "drew", Ratings: 2, Mean: 3, StdDev: 1, Bias: -0.5},
+	}, entries.RaterBiases())
+
+	require.Equal(t, entries, entries.Normalized(NormalizeNone))
+
+	mean := entries.Normalized(NormalizeMean)
+	require.Equal(t, map[string]float64{"drew": 2.5, "james": 4.5}, mean[0].adjusted)
+	require.Equal(t, 3.5, mean[0].averageRating())
+	require.Equal(t, map[string]int{"drew": 2, "james": 5}, mean[0].Ratings, "the raw ratings are kept")
+	require.Equal(t, 2.5, mean[2].averageRating())
+
+	zscore := entries.Normalized(NormalizeZScore)
+	require.InDelta(t, 2.38, zscore[0].adjusted["drew"], 0.01)
+	require.InDelta(t, 4.62, zscore[0].adjusted["james"], 0.01)
+
+	// Everyone else hands out fives, so a five from drew is still the top
+	generous := Entries{
+		{Place: "Pizza Dude", Ratings: map[string]int{"drew": 5, "james": 5}},
+		{Place: "Taco Tuesday", Ratings: map[string]int{"drew": 1, "james": 5}},
+	}
+	require.Equal(t, 5.0, generous.Normalized(NormalizeMean)[0].adjusted["drew"], "kept within the star scale")
+
+	people := mean.PeopleEnhanced()
+	for _, p := range people {
+		if p.Name == "drew" {
+			require.Equal(t, 2.5, p.PlaceAvgRatings["Pizza Dude"])
+		}
+	}
+}
+
+func TestNormalizedSkipsNoRating(t *testing.T) {
+	// Older diaries gave everyone a 0 rating, which means "No Rating"
+	entries := Entries{
+		{Place: "Pizza Dude", Ratings: map[string]int{"drew": 5, "james": 0}},
+		{Place: "Taco Tuesday", Ratings: map[string]int{"drew": 4, "james": 4}},
+	}
+	require.Equal(t, []RaterBias{
+		{Person: "drew", Ratings: 2, Mean: 4.5, StdDev: 0.5, Bias: 0.17},
+		{Person: "james", Ratings: 1, Mean: 4, Bias: -0.33},
+	}, entries.RaterBiases())
+
+	got := entries.Normalized(NormalizeMean)
+	_, ok := got[0].rating("james")
+	require.False(t, ok, "no rating stays no rating")
+	require.Equal(t, 4.83, round2(got[0].averageRating()), "only drew's rating counts")
+	r, _ := got[0].rating("drew")
+	require.Equal(t, 4.83, round2(r))
+	r, _ = got[1].rating("james")
+	require.Equal(t, 4.33, round2(r))
+}
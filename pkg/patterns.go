@@ -0,0 +1,133 @@
+package letseat
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	// MinHabitVisits is how many visits on the same weekday it takes to make a habit
+	MinHabitVisits = 3
+	// HabitShare is how much of a place's visits have to land on the same weekday to make a habit
+	HabitShare = 0.5
+)
+
+// Seasons are the names of the seasons, in the order they are reported
+var Seasons = []string{"winter", "spring", "summer", "fall"}
+
+// seasonIndex returns which of the Seasons a month is in, going by the northern hemisphere
+func seasonIndex(m time.Month) int {
+	return int(m) % 12 / 3
+}
+
+// PatternBucket sums up the meals that fall on a weekday, month or season
+type PatternBucket struct {
+	Label  string `yaml:"label"`
+	Visits int    `yaml:"visits"`
+	Spent  Money  `yaml:"spent"`
+	// AverageRating only counts the meals that were rated
+	AverageRating float64 `yaml:"average_rating"`
+	ratingSum     float64
+	rated         int
+}
+
+func (b *PatternBucket) add(e Entry) {
+	b.Visits++
+	if total, err := e.Cost.Total(); err == nil && !total.IsZero() && total.currency() == DefaultCurrency {
+		b.Spent = NewMoney(b.Spent.Amount+total.Amount, DefaultCurrency)
+	}
+	if len(e.rated()) > 0 {
+		b.ratingSum += e.averageRating()
+		b.rated++
+		b.AverageRating = round2(b.ratingSum / float64(b.rated))
+	}
+}
+
+// Habit is a place that gets visited on the same weekday, over and over
+type Habit struct {
+	Place   string       `yaml:"place"`
+	Weekday time.Weekday `yaml:"weekday"`
+	Visits  int          `yaml:"visits"`
+	// Of is how many visits the place had in all
+	Of int `yaml:"of"`
+}
+
+// String returns a human friendly version of the habit, like "Pizza Dude on Fridays (6 of 8 visits)"
+func (h Habit) String() string {
+	return fmt.Sprintf("%v on %vs (%v of %v visits)", h.Place, h.Weekday, h.Visits, h.Of)
+}
+
+// MarshalYAML writes the weekday out by name, like "Friday"
+func (h Habit) MarshalYAML() (interface{}, error) {
+	return struct {
+		Place   string `yaml:"place"`
+		Weekday string `yaml:"weekday"`
+		Visits  int    `yaml:"visits"`
+		Of      int    `yaml:"of"`
+	}{h.Place, h.Weekday.String(), h.Visits, h.Of}, nil
+}
+
+// Patterns break the meals down by when they happened
+type Patterns struct {
+	Weekdays []PatternBucket `yaml:"weekdays"`
+	Months   []PatternBucket `yaml:"months"`
+	Seasons  []PatternBucket `yaml:"seasons"`
+	Habits   []Habit         `yaml:"habits"`
+}
+
+// Patterns breaks the entries down by weekday, month and season, and picks out habits
+func (e *Entries) Patterns() Patterns {
+	ret := Patterns{
+		Weekdays: make([]PatternBucket, 7),
+		Months:   make([]PatternBucket, 12),
+		Seasons:  make([]PatternBucket, len(Seasons)),
+	}
+	// Weeks start on Monday
+	for idx := range ret.Weekdays {
+		ret.Weekdays[idx].Label = time.Weekday((idx + 1) % 7).String()
+	}
+	for idx := range ret.Months {
+		ret.Months[idx].Label = time.Month(idx + 1).String()
+	}
+	for idx, s := range Seasons {
+		ret.Seasons[idx].Label = s
+	}
+
+	visits := map[string]int{}
+	byDay := map[string]map[time.Weekday]int{}
+	for _, entry := range *e {
+		if entry.Date == nil {
+			continue
+		}
+		wd := entry.Date.Weekday()
+		ret.Weekdays[(int(wd)+6)%7].add(entry)
+		ret.Months[entry.Date.Month()-1].add(entry)
+		ret.Seasons[seasonIndex(entry.Date.Month())].add(entry)
+
+		visits[entry.Place]++
+		if _, ok := byDay[entry.Place]; !ok {
+			byDay[entry.Place] = map[time.Weekday]int{}
+		}
+		byDay[entry.Place][wd]++
+	}
+
+	ret.Habits = []Habit{}
+	for place, days := range byDay {
+		for wd, n := range days {
+			if n >= MinHabitVisits && float64(n)/float64(visits[place]) >= HabitShare {
+				ret.Habits = append(ret.Habits, Habit{Place: place, Weekday: wd, Visits: n, Of: visits[place]})
+			}
+		}
+	}
+	sort.Slice(ret.Habits, func(i, j int) bool {
+		if ret.Habits[i].Visits != ret.Habits[j].Visits {
+			return ret.Habits[i].Visits > ret.Habits[j].Visits
+		}
+		if ret.Habits[i].Place != ret.Habits[j].Place {
+			return ret.Habits[i].Place < ret.Habits[j].Place
+		}
+		return ret.Habits[i].Weekday < ret.Habits[j].Weekday
+	})
+	return ret
+}
@@ -0,0 +1,69 @@
+package letseat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestPatterns(t *testing.T) {
+	meal := func(place, date string, rating int) Entry {
+		e := Entry{Place: place, Date: toPTR(mustTime(date))}
+		if rating > 0 {
+			e.Ratings = map[string]int{"drew": rating}
+		}
+		return e
+	}
+	entries := Entries{
+		meal("Pizza Dude", "2024-01-05 19:00", 4),   // Friday
+		meal("Pizza Dude", "2024-01-12 19:00", 0),   // Friday
+		meal("Pizza Dude", "2024-01-19 19:00", 5),   // Friday
+		meal("Pizza Dude", "2024-01-23 19:00", 3),   // Tuesday
+		meal("Taco Tuesday", "2024-07-02 12:00", 4), // Tuesday
+		meal("Taco Tuesday", "2024-07-09 12:00", 2), // Tuesday
+		meal("Taco Tuesday", "2024-07-10 12:00", 0), // Wednesday
+	}
+	entries[6].Ratings = map[string]int{"drew": 0}
+	entries[0].Cost = Bill{Subtotal: MustParseMoney("20", "USD")}
+	entries[2].Cost = Bill{Subtotal: MustParseMoney("25", "USD")}
+
+	got := entries.Patterns()
+	require.Len(t, got.Weekdays, 7)
+	require.Equal(t, "Monday", got.Weekdays[0].Label)
+	require.Equal(t, "Sunday", got.Weekdays[6].Label)
+	friday := got.Weekdays[4]
+	require.Equal(t, 3, friday.Visits)
+	require.Equal(t, "$45.00", friday.Spent.String())
+	require.Equal(t, 4.5, friday.AverageRating, "unrated meals don't drag the average down")
+	require.Equal(t, 1, got.Weekdays[2].Visits)
+	require.Equal(t, 0.0, got.Weekdays[2].AverageRating, "0 means nobody rated it")
+	require.Equal(t, 3, got.Weekdays[1].Visits)
+
+	require.Equal(t, 4, got.Months[0].Visits)
+	require.Equal(t, 3, got.Months[6].Visits)
+	require.Equal(t, []string{"winter", "spring", "summer", "fall"}, []string{got.Seasons[0].Label, got.Seasons[1].Label, got.Seasons[2].Label, got.Seasons[3].Label})
+	require.Equal(t, 4, got.Seasons[0].Visits)
+	require.Equal(t, 3, got.Seasons[2].Visits)
+
+	require.Equal(t, []Habit{{Place: "Pizza Dude", Weekday: time.Friday, Visits: 3, Of: 4}}, got.Habits, "Taco Tuesday needs more visits")
+	require.Equal(t, "Pizza Dude on Fridays (3 of 4 visits)", got.Habits[0].String())
+}
+
+func TestPatternsHabitOrder(t *testing.T) {
+	var entries Entries
+	// Three Fridays and three Mondays, so both are habits with the same visits
+	for _, date := range []string{"2024-01-05", "2024-01-12", "2024-01-19", "2024-01-01", "2024-01-08", "2024-01-15"} {
+		entries = append(entries, Entry{Place: "Pizza Dude", Date: toPTR(mustTime(date + " 19:00"))})
+	}
+	got := entries.Patterns().Habits
+	require.Equal(t, []Habit{
+		{Place: "Pizza Dude", Weekday: time.Monday, Visits: 3, Of: 6},
+		{Place: "Pizza Dude", Weekday: time.Friday, Visits: 3, Of: 6},
+	}, got)
+
+	out, err := yaml.Marshal(got[:1])
+	require.NoError(t, err)
+	require.Equal(t, "- place: Pizza Dude\n  weekday: Monday\n  visits: 3\n  of: 6\n", string(out))
+}
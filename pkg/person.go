@@ -7,6 +7,7 @@ import (
 // Person represents a person who ate and rated something at a restaurant
 type Person struct {
 	Name            string
+	Visits          int
 	PlaceAvgRatings map[string]float64
 }
 
@@ -80,3 +80,51 @@ func TestFavoriteN(t *testing.T) {
 		}
 	}
 }
+
+func TestPeopleWithAttendees(t *testing.T) {
+	e := Entries{
+		Entry{Place: "A", Attendees: []string{"a", "b"}, Guests: []string{"grandma"}, Ratings: map[string]int{"a": 4, "grandma": 5}},
+		Entry{Place: "B", Ratings: map[string]int{"a": 3}},
+		Entry{Place: "C", Attendees: []string{"b"}},
+	}
+	require.ElementsMatch(t, []string{"a", "b"}, e.people(), "guests are not regulars")
+
+	visits := map[string]int{}
+	for _, person := range e.PeopleEnhanced() {
+		visits[person.Name] = person.Visits
+	}
+	require.Equal(t, map[string]int{"a": 2, "b": 2}, visits)
+}
+
+func TestDiners(t *testing.T) {
+	require.Equal(
+		t,
+		[]string{"a", "b", "grandma"},
+		Entry{Attendees: []string{"a", "b"}, Guests: []string{"grandma"}}.Diners(),
+	)
+	require.Equal(
+		t,
+		[]string{"a", "b"},
+		Entry{Ratings: map[string]int{"b": 3, "a": 4}}.Diners(),
+		"older entries only know about raters",
+	)
+	require.True(t, Entry{Ratings: map[string]int{"a": 4}}.Attended("a"))
+	require.False(t, Entry{Attendees: []string{"b"}, Ratings: map[string]int{"a": 4}}.Attended("a"))
+	require.True(t, Entry{Guests: []string{"grandma"}}.Attended("grandma"))
+}
+
+func TestDinersZeroRatings(t *testing.T) {
+	// Older diaries gave everyone a 0 rating, whether they were there or not
+	old := Entry{Place: "A", Ratings: map[string]int{"drew": 4, "james": 0}}
+	require.Equal(t, []string{"drew"}, old.Diners())
+	require.True(t, old.Attended("drew"))
+	require.False(t, old.Attended("james"))
+
+	e := Entries{old}
+	visits := map[string]int{}
+	for _, person := range e.PeopleEnhanced() {
+		visits[person.Name] = person.Visits
+	}
+	require.Equal(t, 1, visits["drew"])
+	require.Zero(t, visits["james"])
+}
@@ -1,7 +1,9 @@
 package letseat
 
 import (
+	"encoding/json"
 	"errors"
+	"slices"
 	"time"
 
 	"github.com/gosimple/slug"
@@ -9,10 +11,18 @@ import (
 
 // Place is a restaurant, or place you can eat
 type Place struct {
-	Name   string `yaml:"name"`
-	Slug   string `yaml:"slug"`
-	Tier   int    `yaml:"tier"`
-	Format Format `yaml:"format"`
+	Name         string       `yaml:"name"`
+	Slug         string       `yaml:"slug"`
+	Tier         int          `yaml:"tier"`
+	Format       Format       `yaml:"format"`
+	Address      string       `yaml:"address,omitempty"`
+	Neighborhood string       `yaml:"neighborhood,omitempty"`
+	Location     *Coordinates `yaml:"location,omitempty"`
+	Hours        *Hours       `yaml:"hours,omitempty"`
+	Status       PlaceStatus  `yaml:"status,omitempty"`
+	Season       []time.Month `yaml:"season,omitempty"`
+	Brand        string       `yaml:"brand,omitempty"`
+	Price        PriceLevel   `yaml:"price,omitempty"`
 }
 
 // PlaceDetail is the overview detail thing of a place
@@ -20,7 +30,28 @@ type PlaceDetail struct {
 	Name          string
 	AverageRating float64
 	LastVisit     *time.Time
-	Visits        int
+	// FirstVisit is the earliest of the visits the detail was summarized from
+	FirstVisit *time.Time
+	Visits     int
+	// MedianCost is the median spent per person, if we know it
+	MedianCost *Money
+	// ActualPrice is the price level going by what was actually spent
+	ActualPrice PriceLevel
+	// Cadence is the usual time between visits, or 0 if there aren't enough to tell
+	Cadence time.Duration
+	// Trend is how the ratings are moving, if there are enough visits to tell
+	Trend *Trend
+	// BayesianRating is the average rating, pulled towards the average of every
+	// place until there are enough visits to trust it. Set by Score
+	BayesianRating float64
+	// Confidence is where the true average rating most likely is. Set by Score
+	Confidence Interval
+	// Place is the place from the registry, if we know about it
+	Place *Place
+	// Locations are all the places that make up a brand, when grouping by brand
+	Locations []*Place
+	// ratings are the average rating of each rated visit
+	ratings []float64
 }
 
 // PlaceDetails represents multiple PlaceDetail items. Satisfies the Sortable interface
@@ -86,4 +117,90 @@ type Format struct {
 	TakeOut   bool
 	FoodTruck bool
 	Counter   bool
+	Delivery  bool
+	DriveThru bool
+}
+
+// IsZero returns true if nothing is known about the format
+func (f Format) IsZero() bool {
+	return f == Format{}
+}
+
+// Modes returns the service modes supported by a Format. If we don't know
+// anything about the format, every mode is returned
+func (f Format) Modes() []Mode {
+	if f.IsZero() {
+		return slices.Clone(AllModes)
+	}
+	ret := []Mode{}
+	if f.DineIn || f.Counter {
+		ret = append(ret, ModeDineIn)
+	}
+	if f.TakeOut || f.Counter {
+		ret = append(ret, ModeTakeout)
+	}
+	if f.Delivery {
+		ret = append(ret, ModeDelivery)
+	}
+	if f.DriveThru {
+		ret = append(ret, ModeDriveThru)
+	}
+	if f.FoodTruck {
+		ret = append(ret, ModeFoodTruck)
+	}
+	return ret
+}
+
+// Supports returns true if the format allows the given mode
+func (f Format) Supports(m Mode) bool {
+	return slices.Contains(f.Modes(), m)
+}
+
+// FormatWithModes returns a Format supporting the given modes
+func FormatWithModes(modes ...Mode) Format {
+	var f Format
+	for _, m := range modes {
+		switch m {
+		case ModeDineIn:
+			f.DineIn = true
+		case ModeTakeout:
+			f.TakeOut = true
+		case ModeDelivery:
+			f.Delivery = true
+		case ModeDriveThru:
+			f.DriveThru = true
+		case ModeFoodTruck:
+			f.FoodTruck = true
+		}
+	}
+	return f
+}
+
+// Find returns the place matching a given name, or nil if it isn't known
+func (p Places) Find(name string) *Place {
+	s := slug.Make(name)
+	for _, item := range p {
+		if item.Slug == s {
+			item := item
+			return &item
+		}
+	}
+	return nil
+}
+
+// Names returns the names of all the places
+func (p Places) Names() []string {
+	ret := make([]string, len(p))
+	for idx, item := range p {
+		ret[idx] = item.Name
+	}
+	return ret
+}
+
+func (p Place) mustMarshal() []byte {
+	got, err := json.Marshal(p)
+	if err != nil {
+		panic(err)
+	}
+	return got
 }
@@ -30,3 +30,11 @@ func TestNewPlaceEmptyName(t *testing.T) {
 	require.Nil(t, got)
 	require.EqualError(t, err, "name cannot be empty")
 }
+
+func TestFormatModes(t *testing.T) {
+	require.Equal(t, AllModes, Format{}.Modes(), "unknown formats support everything")
+	require.Equal(t, []Mode{ModeDineIn, ModeTakeout}, Format{Counter: true}.Modes())
+	require.Equal(t, []Mode{ModeTakeout, ModeDelivery}, FormatWithModes(ModeDelivery, ModeTakeout).Modes())
+	require.True(t, Format{FoodTruck: true}.Supports(ModeFoodTruck))
+	require.False(t, Format{FoodTruck: true}.Supports(ModeDineIn))
+}
@@ -0,0 +1,125 @@
+package letseat
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// PriceLevel is how expensive a place is, from $ to $$$$. Zero means we don't know
+type PriceLevel int
+
+// MaxPriceLevel is the most expensive a place can be
+const MaxPriceLevel PriceLevel = 4
+
+// PriceBands are the most you'd spend per person, in whole units of the
+// default currency, at each price level below the top one. Anything more than
+// the last band is $$$$
+var PriceBands = []float64{15, 30, 60}
+
+// ParsePriceLevel reads a price level like "$$" or "2"
+func ParsePriceLevel(s string) (PriceLevel, error) {
+	s = strings.TrimSpace(s)
+	if s != "" && strings.Trim(s, "$") == "" {
+		if len(s) > int(MaxPriceLevel) {
+			return 0, fmt.Errorf("price must be between $ and $$$$: %v", s)
+		}
+		return PriceLevel(len(s)), nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 || n > int(MaxPriceLevel) {
+		return 0, fmt.Errorf("price must be between 1 and %v, or $ to $$$$: %v", int(MaxPriceLevel), s)
+	}
+	return PriceLevel(n), nil
+}
+
+// String returns the price level like "$$", or "?" if we don't know it
+func (p PriceLevel) String() string {
+	if p <= 0 {
+		return "?"
+	}
+	return strings.Repeat("$", int(p))
+}
+
+// PriceLevelFor returns the price level for spending a given amount per person
+func PriceLevelFor(perPerson Money) PriceLevel {
+	v := perPerson.Float64()
+	for idx, band := range PriceBands {
+		if v <= band {
+			return PriceLevel(idx + 1)
+		}
+	}
+	return PriceLevel(len(PriceBands) + 1)
+}
+
+// CostPerPerson splits the bill between everyone who was there. Returns false
+// if we don't know the cost, or who was there
+func (d Entry) CostPerPerson() (Money, bool) {
+	diners := len(d.Diners())
+	if d.Cost.IsZero() || diners == 0 {
+		return Money{}, false
+	}
+	total, err := d.Cost.Total()
+	if err != nil {
+		return Money{}, false
+	}
+	return total.Div(diners), true
+}
+
+// MedianCostPerPerson returns the median spend per person across the entries,
+// in the default currency. Returns false if none of the entries have a cost
+func (e *Entries) MedianCostPerPerson() (Money, bool) {
+	amounts := []int64{}
+	for _, entry := range *e {
+		if m, ok := entry.CostPerPerson(); ok && m.currency() == DefaultCurrency {
+			amounts = append(amounts, m.Amount)
+		}
+	}
+	if len(amounts) == 0 {
+		return Money{}, false
+	}
+	slices.Sort(amounts)
+	mid := len(amounts) / 2
+	if len(amounts)%2 == 1 {
+		return NewMoney(amounts[mid], DefaultCurrency), true
+	}
+	return NewMoney(amounts[mid-1]+amounts[mid], DefaultCurrency).Div(2), true
+}
+
+// SuggestedPrice guesses a price level from what was actually spent
+func (e *Entries) SuggestedPrice() PriceLevel {
+	m, ok := e.MedianCostPerPerson()
+	if !ok {
+		return 0
+	}
+	return PriceLevelFor(m)
+}
+
+// DeclaredPrice is the price level set on the place, if we know about it
+func (p PlaceDetail) DeclaredPrice() PriceLevel {
+	if p.Place == nil {
+		return 0
+	}
+	return p.Place.Price
+}
+
+// PriceComparison describes how the actual spend compares to the declared
+// price level, like "$$ declared, $$$ actual". Returns an empty string if
+// there's nothing to compare
+func (p PlaceDetail) PriceComparison() string {
+	declared := p.DeclaredPrice()
+	if declared == 0 || p.ActualPrice == 0 {
+		return ""
+	}
+	var verdict string
+	switch {
+	case p.ActualPrice > declared:
+		verdict = "pricier than expected"
+	case p.ActualPrice < declared:
+		verdict = "cheaper than expected"
+	default:
+		verdict = "as expected"
+	}
+	return fmt.Sprintf("%v declared, %v actual (%v)", declared, p.ActualPrice, verdict)
+}
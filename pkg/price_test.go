@@ -0,0 +1,108 @@
+package letseat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePriceLevel(t *testing.T) {
+	tests := map[string]struct {
+		given     string
+		expect    PriceLevel
+		expectErr string
+	}{
+		"dollars":     {given: "$$", expect: 2},
+		"number":      {given: "4", expect: 4},
+		"too-many":    {given: "$$$$$", expectErr: "price must be between $ and $$$$: $$$$$"},
+		"zero":        {given: "0", expectErr: "price must be between 1 and 4, or $ to $$$$: 0"},
+		"not-a-price": {given: "cheap", expectErr: "price must be between 1 and 4, or $ to $$$$: cheap"},
+	}
+	for desc, tt := range tests {
+		got, err := ParsePriceLevel(tt.given)
+		if tt.expectErr != "" {
+			require.EqualError(t, err, tt.expectErr, desc)
+			continue
+		}
+		require.NoError(t, err, desc)
+		require.Equal(t, tt.expect, got, desc)
+	}
+	require.Equal(t, "$$$", PriceLevel(3).String())
+	require.Equal(t, "?", PriceLevel(0).String())
+}
+
+func TestPriceLevelFor(t *testing.T) {
+	for given, expect := range map[string]PriceLevel{
+		"9.99":  1,
+		"15.00": 1,
+		"15.01": 2,
+		"45":    3,
+		"120":   4,
+	} {
+		require.Equal(t, expect, PriceLevelFor(MustParseMoney(given, "USD")), given)
+	}
+}
+
+func TestCostPerPerson(t *testing.T) {
+	e := Entry{
+		Cost:      Bill{Subtotal: MustParseMoney("40", "USD"), Tip: MustParseMoney("8", "USD")},
+		Attendees: []string{"drew", "james"},
+		Guests:    []string{"grandma"},
+	}
+	got, ok := e.CostPerPerson()
+	require.True(t, ok)
+	require.Equal(t, "$16.00", got.String())
+
+	_, ok = Entry{Attendees: []string{"drew"}}.CostPerPerson()
+	require.False(t, ok, "no cost")
+	_, ok = Entry{Cost: Bill{Subtotal: MustParseMoney("10", "USD")}}.CostPerPerson()
+	require.False(t, ok, "nobody was there")
+
+	old := Entry{Cost: Bill{Subtotal: MustParseMoney("40", "USD")}, Ratings: map[string]int{"drew": 4, "james": 0}}
+	got, ok = old.CostPerPerson()
+	require.True(t, ok)
+	require.Equal(t, "$40.00", got.String(), "a 0 rating from an older entry doesn't mean james was there")
+}
+
+func TestMedianCostPerPerson(t *testing.T) {
+	entry := func(cost string) Entry {
+		return Entry{Cost: Bill{Subtotal: MustParseMoney(cost, "USD")}, Attendees: []string{"drew"}}
+	}
+	entries := Entries{entry("10"), entry("50"), entry("20"), {Attendees: []string{"drew"}}}
+	got, ok := entries.MedianCostPerPerson()
+	require.True(t, ok)
+	require.Equal(t, "$20.00", got.String())
+	require.Equal(t, PriceLevel(2), entries.SuggestedPrice())
+
+	entries = append(entries, entry("30"))
+	got, _ = entries.MedianCostPerPerson()
+	require.Equal(t, "$25.00", got.String(), "even counts average the middle two")
+
+	empty := Entries{}
+	require.Equal(t, PriceLevel(0), empty.SuggestedPrice())
+}
+
+func TestPriceComparison(t *testing.T) {
+	p := PlaceDetail{Place: &Place{Price: 2}, ActualPrice: 3}
+	require.Equal(t, "$$ declared, $$$ actual (pricier than expected)", p.PriceComparison())
+	p.ActualPrice = 2
+	require.Equal(t, "$$ declared, $$ actual (as expected)", p.PriceComparison())
+	require.Equal(t, "", PlaceDetail{ActualPrice: 2}.PriceComparison())
+}
+
+func TestFilterMaxPrice(t *testing.T) {
+	db := newTestDB(t)
+	d := New(WithDB(db))
+	require.NoError(t, d.Log(Entry{Place: "Fancy Spot"}))
+	require.NoError(t, d.Log(Entry{Place: "Taco Tuesday"}))
+	require.NoError(t, d.Log(Entry{Place: "Mystery Spot"}))
+	fancy := MustNewPlace(WithName("Fancy Spot"))
+	fancy.Price = 4
+	tacos := MustNewPlace(WithName("Taco Tuesday"))
+	tacos.Price = 1
+	require.NoError(t, d.SavePlace(*fancy))
+	require.NoError(t, d.SavePlace(*tacos))
+
+	got := New(WithDB(db), WithFilter(EntryFilter{MaxPrice: 2})).Entries()
+	require.Equal(t, []string{"Mystery Spot", "Taco Tuesday"}, got.UniquePlaceNames(), "places without a price are kept")
+}
@@ -0,0 +1,112 @@
+package letseat
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/montanaflynn/stats"
+)
+
+// PriorVisits is how many average visits every place starts out with when
+// working out the Bayesian rating. Places need more real visits than this
+// before their own ratings win out
+const PriorVisits = 3
+
+// RankBy is how places are ranked from best to worst
+type RankBy string
+
+const (
+	// RankByAverage ranks by the plain average rating
+	RankByAverage RankBy = "average"
+	// RankByBayesian ranks by the average, pulled towards everyone's average
+	// until there are enough visits to trust it
+	RankByBayesian RankBy = "bayesian"
+	// RankByConfidence ranks by the low end of the confidence interval, so
+	// only places that are reliably good come out on top
+	RankByConfidence RankBy = "confidence"
+)
+
+// ParseRankBy returns a RankBy from a string
+func ParseRankBy(s string) (RankBy, error) {
+	switch r := RankBy(s); r {
+	case RankByAverage, RankByBayesian, RankByConfidence:
+		return r, nil
+	}
+	return "", fmt.Errorf("unknown ranking: %v, must be one of average, bayesian or confidence", s)
+}
+
+// Interval is a range a rating most likely falls in, at 95% confidence
+type Interval struct {
+	Low  float64
+	High float64
+}
+
+// String returns a human friendly version of the interval, like "3.8-4.6"
+func (i Interval) String() string {
+	return fmt.Sprintf("%.1f-%.1f", i.Low, i.High)
+}
+
+// Rated returns how many visits to the place were rated
+func (p PlaceDetail) Rated() int {
+	return len(p.ratings)
+}
+
+// Score works out the Bayesian rating and confidence interval of each place,
+// compared with all the others
+func (p PlaceDetails) Score() {
+	all := []float64{}
+	for _, d := range p {
+		all = append(all, d.ratings...)
+	}
+	if len(all) == 0 {
+		return
+	}
+	mean, _ := stats.Mean(all)
+	for idx := range p {
+		p[idx].score(mean)
+	}
+}
+
+func (p *PlaceDetail) score(prior float64) {
+	n := float64(len(p.ratings))
+	var sum float64
+	for _, r := range p.ratings {
+		sum += r
+	}
+	p.BayesianRating = (PriorVisits*prior + sum) / (PriorVisits + n)
+	if len(p.ratings) < 2 {
+		// One visit could be a fluke, so it could be anything
+		p.Confidence = Interval{Low: minStars, High: maxStars}
+		return
+	}
+	mean := sum / n
+	sdev, _ := stats.StandardDeviationSample(p.ratings)
+	margin := tCritical(len(p.ratings)-1) * sdev / math.Sqrt(n)
+	p.Confidence = Interval{
+		Low:  math.Max(minStars, mean-margin),
+		High: math.Min(maxStars, mean+margin),
+	}
+}
+
+// ScoreFor returns the rating the place is ranked on
+func (p PlaceDetail) ScoreFor(r RankBy) float64 {
+	switch r {
+	case RankByBayesian:
+		return p.BayesianRating
+	case RankByConfidence:
+		return p.Confidence.Low
+	default:
+		return p.AverageRating
+	}
+}
+
+// SortBy sorts the places from best to worst. Ties go to the place with more visits
+func (p PlaceDetails) SortBy(r RankBy) {
+	sort.SliceStable(p, func(i, j int) bool {
+		if ki, kj := p[i].ScoreFor(r), p[j].ScoreFor(r); ki != kj {
+			return ki > kj
+		}
+		return p[i].Visits > p[j].Visits
+	})
+}
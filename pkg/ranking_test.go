@@ -0,0 +1,48 @@
+package letseat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRankBy(t *testing.T) {
+	got, err := ParseRankBy("bayesian")
+	require.NoError(t, err)
+	require.Equal(t, RankByBayesian, got)
+
+	_, err = ParseRankBy("vibes")
+	require.EqualError(t, err, "unknown ranking: vibes, must be one of average, bayesian or confidence")
+}
+
+func TestScore(t *testing.T) {
+	pd := PlaceDetails{
+		{Name: "One Hit Wonder", AverageRating: 5, Visits: 1, ratings: []float64{5}},
+		{Name: "Old Reliable", AverageRating: 4.5, Visits: 6, ratings: []float64{4, 5, 4, 5, 4, 5}},
+		{Name: "Hit or Miss", AverageRating: 3, Visits: 4, ratings: []float64{1, 5, 1, 5}},
+		{Name: "Never Rated", Visits: 1},
+	}
+	pd.Score()
+	// Everyone's average is 44/11
+	require.InDelta(t, 4.25, pd[0].BayesianRating, 0.01)
+	require.InDelta(t, 4.33, pd[1].BayesianRating, 0.01)
+	require.Equal(t, Interval{Low: 1, High: 5}, pd[0].Confidence, "one visit tells us nothing")
+	require.Equal(t, "3.9-5.0", pd[1].Confidence.String())
+	require.Equal(t, Interval{Low: 1, High: 5}, pd[2].Confidence, "clamped to the star scale")
+	require.InDelta(t, 4, pd[3].BayesianRating, 0.01, "places without ratings get everyone's average")
+	require.Equal(t, 6, pd[1].Rated())
+
+	names := func() []string {
+		ret := make([]string, len(pd))
+		for idx, d := range pd {
+			ret[idx] = d.Name
+		}
+		return ret
+	}
+	pd.SortBy(RankByAverage)
+	require.Equal(t, []string{"One Hit Wonder", "Old Reliable", "Hit or Miss", "Never Rated"}, names())
+	pd.SortBy(RankByBayesian)
+	require.Equal(t, []string{"Old Reliable", "One Hit Wonder", "Never Rated", "Hit or Miss"}, names())
+	pd.SortBy(RankByConfidence)
+	require.Equal(t, []string{"Old Reliable", "Hit or Miss", "One Hit Wonder", "Never Rated"}, names())
+}
@@ -0,0 +1,128 @@
+package letseat
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// PlaceStatus is whether or not a place is still around
+type PlaceStatus string
+
+const (
+	// StatusActive is a place that is open for business. Places without a status are active
+	StatusActive PlaceStatus = "active"
+	// StatusTemporarilyClosed is a place that is closed for now, but should be back
+	StatusTemporarilyClosed PlaceStatus = "temporarily-closed"
+	// StatusSeasonal is a place that is only open during some months of the year
+	StatusSeasonal PlaceStatus = "seasonal"
+	// StatusClosed is a place that is gone for good
+	StatusClosed PlaceStatus = "closed"
+)
+
+// String satisfies the Stringer interface
+func (s PlaceStatus) String() string {
+	if s == "" {
+		return string(StatusActive)
+	}
+	return string(s)
+}
+
+// ParsePlaceStatus returns a PlaceStatus from a string
+func ParsePlaceStatus(s string) (PlaceStatus, error) {
+	k := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(s)), " ", "-")
+	switch st := PlaceStatus(k); st {
+	case StatusActive, StatusTemporarilyClosed, StatusSeasonal, StatusClosed:
+		return st, nil
+	}
+	return "", fmt.Errorf("unknown place status: %v", s)
+}
+
+// ParseMonth reads a month like "jun" or "June"
+func ParseMonth(s string) (time.Month, error) {
+	k := strings.ToLower(strings.TrimSpace(s))
+	if len(k) >= 3 {
+		for m := time.January; m <= time.December; m++ {
+			if strings.HasPrefix(strings.ToLower(m.String()), k) {
+				return m, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unknown month: %v", s)
+}
+
+// ParseMonths reads months like "may-sep" or "dec,jan,feb". Ranges can wrap
+// around the end of the year
+func ParseMonths(s string) ([]time.Month, error) {
+	ret := []time.Month{}
+	for _, part := range strings.Split(s, ",") {
+		first, last, isRange := strings.Cut(part, "-")
+		start, err := ParseMonth(first)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, start)
+		if !isRange {
+			continue
+		}
+		end, err := ParseMonth(last)
+		if err != nil {
+			return nil, err
+		}
+		for m := start; m != end; {
+			m = m%12 + 1
+			ret = append(ret, m)
+		}
+	}
+	return ret, nil
+}
+
+// IsClosed returns true if the place is closed, either for good or just for now
+func (p Place) IsClosed() bool {
+	return p.Status == StatusClosed || p.Status == StatusTemporarilyClosed
+}
+
+// IsClosed returns true if the place we know about is closed. Brands are only
+// closed once all of their locations are
+func (p PlaceDetail) IsClosed() bool {
+	if len(p.Locations) > 0 {
+		for _, l := range p.Locations {
+			if !l.IsClosed() {
+				return false
+			}
+		}
+		return true
+	}
+	return p.Place != nil && p.Place.IsClosed()
+}
+
+// IsActiveOn returns true if the place is operating on the given date. Closed
+// places never are, and seasonal places are only during their season
+func (p Place) IsActiveOn(t time.Time) bool {
+	switch p.Status {
+	case StatusClosed, StatusTemporarilyClosed:
+		return false
+	case StatusSeasonal:
+		return len(p.Season) == 0 || slices.Contains(p.Season, t.Month())
+	}
+	return true
+}
+
+// Close marks a place as closed, either temporarily or for good
+func (p *Place) Close(temporary bool) {
+	if temporary {
+		p.Status = StatusTemporarilyClosed
+		return
+	}
+	p.Status = StatusClosed
+}
+
+// Reopen marks a place as open again. Seasonal places go back to being seasonal
+func (p *Place) Reopen() {
+	if len(p.Season) > 0 {
+		p.Status = StatusSeasonal
+		return
+	}
+	p.Status = StatusActive
+}
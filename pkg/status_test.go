@@ -0,0 +1,86 @@
+package letseat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePlaceStatus(t *testing.T) {
+	for given, expect := range map[string]PlaceStatus{
+		"active":             StatusActive,
+		"Temporarily Closed": StatusTemporarilyClosed,
+		"seasonal":           StatusSeasonal,
+		"CLOSED":             StatusClosed,
+	} {
+		got, err := ParsePlaceStatus(given)
+		require.NoError(t, err, given)
+		require.Equal(t, expect, got, given)
+	}
+	_, err := ParsePlaceStatus("haunted")
+	require.EqualError(t, err, "unknown place status: haunted")
+	require.Equal(t, "active", PlaceStatus("").String())
+}
+
+func TestParseMonths(t *testing.T) {
+	tests := map[string]struct {
+		given     string
+		expect    []time.Month
+		expectErr string
+	}{
+		"single": {given: "june", expect: []time.Month{time.June}},
+		"range":  {given: "may-aug", expect: []time.Month{time.May, time.June, time.July, time.August}},
+		"wraps":  {given: "nov-feb", expect: []time.Month{time.November, time.December, time.January, time.February}},
+		"list":   {given: "jan,mar", expect: []time.Month{time.January, time.March}},
+		"bad":    {given: "smarch", expectErr: "unknown month: smarch"},
+		"short":  {given: "ju", expectErr: "unknown month: ju"},
+	}
+	for desc, tt := range tests {
+		got, err := ParseMonths(tt.given)
+		if tt.expectErr != "" {
+			require.EqualError(t, err, tt.expectErr, desc)
+			continue
+		}
+		require.NoError(t, err, desc)
+		require.Equal(t, tt.expect, got, desc)
+	}
+}
+
+func TestPlaceIsActiveOn(t *testing.T) {
+	july := mustTime("2024-07-04 12:00")
+	jan := mustTime("2024-01-04 12:00")
+	p := MustNewPlace(WithName("Custard Stand"))
+	require.True(t, p.IsActiveOn(jan), "places without a status are active")
+
+	p.Status = StatusSeasonal
+	p.Season = []time.Month{time.May, time.June, time.July, time.August}
+	require.True(t, p.IsActiveOn(july))
+	require.False(t, p.IsActiveOn(jan))
+
+	p.Close(true)
+	require.Equal(t, StatusTemporarilyClosed, p.Status)
+	require.False(t, p.IsActiveOn(july))
+	require.True(t, p.IsClosed())
+	require.True(t, PlaceDetail{Place: p}.IsClosed())
+
+	p.Reopen()
+	require.Equal(t, StatusSeasonal, p.Status, "seasonal places go back to being seasonal")
+
+	p.Season = nil
+	p.Close(false)
+	require.Equal(t, StatusClosed, p.Status)
+	p.Reopen()
+	require.Equal(t, StatusActive, p.Status)
+}
+
+func TestPlaceFilterActiveOn(t *testing.T) {
+	now := mustTime("2024-07-04 12:00")
+	p := MustNewPlace(WithName("Gone Burger"))
+	details := PlaceDetails{{Name: "Gone Burger", Place: p}, {Name: "Unknown"}}
+	require.Len(t, details.Filter(PlaceFilter{ActiveOn: &now}), 2)
+	p.Close(false)
+	got := details.Filter(PlaceFilter{ActiveOn: &now})
+	require.Len(t, got, 1)
+	require.Equal(t, "Unknown", got[0].Name)
+}
@@ -0,0 +1,101 @@
+package letseat
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TierNames says what each Place.Tier means, like "everyday" or "treat"
+type TierNames map[int]string
+
+// DefaultTierNames are used when the config file doesn't set any
+var DefaultTierNames = TierNames{
+	1: "everyday",
+	2: "treat",
+	3: "special-occasion",
+}
+
+// Name returns the name of a tier, or an empty string if it isn't set
+func (t TierNames) Name(tier int) string {
+	if tier == 0 {
+		return ""
+	}
+	if n, ok := t[tier]; ok {
+		return n
+	}
+	return fmt.Sprintf("tier %v", tier)
+}
+
+// Parse returns a tier from its name or number
+func (t TierNames) Parse(s string) (int, error) {
+	k := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(s)), " ", "-")
+	for tier, name := range t {
+		if strings.ReplaceAll(strings.ToLower(name), " ", "-") == k {
+			return tier, nil
+		}
+	}
+	if n, err := strconv.Atoi(k); err == nil && n > 0 {
+		return n, nil
+	}
+	return 0, fmt.Errorf("unknown tier: %v, must be one of %v", s, strings.Join(t.Names(), ", "))
+}
+
+// ParseMany returns the tiers for multiple names or numbers
+func (t TierNames) ParseMany(s []string) ([]int, error) {
+	ret := make([]int, len(s))
+	for idx, item := range s {
+		tier, err := t.Parse(item)
+		if err != nil {
+			return nil, err
+		}
+		ret[idx] = tier
+	}
+	return ret, nil
+}
+
+// Names returns the tier names, in tier order
+func (t TierNames) Names() []string {
+	tiers := make([]int, 0, len(t))
+	for tier := range t {
+		tiers = append(tiers, tier)
+	}
+	sort.Ints(tiers)
+	ret := make([]string, len(tiers))
+	for idx, tier := range tiers {
+		ret[idx] = t[tier]
+	}
+	return ret
+}
+
+// Occasions maps an occasion, like "date-night", to the tiers that suit it
+type Occasions map[string][]string
+
+// DefaultOccasions are used when the config file doesn't set any
+var DefaultOccasions = Occasions{
+	"weeknight":   {"everyday"},
+	"date-night":  {"treat", "special-occasion"},
+	"celebration": {"special-occasion"},
+}
+
+// Tiers returns the tiers that suit an occasion
+func (o Occasions) Tiers(occasion string, names TierNames) ([]int, error) {
+	tiers, ok := o[strings.ToLower(occasion)]
+	if !ok {
+		known := make([]string, 0, len(o))
+		for k := range o {
+			known = append(known, k)
+		}
+		sort.Strings(known)
+		return nil, fmt.Errorf("unknown occasion: %v, must be one of %v", occasion, strings.Join(known, ", "))
+	}
+	return names.ParseMany(tiers)
+}
+
+// matchTier returns true if the place is in one of the tiers. Places without
+// a tier never match
+func matchTier(p *Place, tiers []int) bool {
+	return p != nil && slices.Contains(tiers, p.Tier)
+}
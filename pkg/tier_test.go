@@ -0,0 +1,49 @@
+package letseat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTierNames(t *testing.T) {
+	names := DefaultTierNames
+	require.Equal(t, "treat", names.Name(2))
+	require.Equal(t, "", names.Name(0))
+	require.Equal(t, "tier 7", names.Name(7))
+	require.Equal(t, []string{"everyday", "treat", "special-occasion"}, names.Names())
+
+	for given, expect := range map[string]int{
+		"Everyday":         1,
+		"special occasion": 3,
+		"2":                2,
+	} {
+		got, err := names.Parse(given)
+		require.NoError(t, err, given)
+		require.Equal(t, expect, got, given)
+	}
+	_, err := names.Parse("fancy")
+	require.EqualError(t, err, "unknown tier: fancy, must be one of everyday, treat, special-occasion")
+}
+
+func TestOccasionTiers(t *testing.T) {
+	got, err := DefaultOccasions.Tiers("date-night", DefaultTierNames)
+	require.NoError(t, err)
+	require.Equal(t, []int{2, 3}, got)
+
+	_, err = DefaultOccasions.Tiers("brunch", DefaultTierNames)
+	require.EqualError(t, err, "unknown occasion: brunch, must be one of celebration, date-night, weeknight")
+
+	_, err = Occasions{"lunch": {"cheap"}}.Tiers("lunch", DefaultTierNames)
+	require.EqualError(t, err, "unknown tier: cheap, must be one of everyday, treat, special-occasion")
+}
+
+func TestPlaceFilterTiers(t *testing.T) {
+	treat := &Place{Name: "Fancy Spot", Tier: 2}
+	f := PlaceFilter{Tiers: []int{2, 3}}
+	require.True(t, f.Match(treat))
+	require.False(t, f.Match(&Place{Name: "Taco Tuesday", Tier: 1}))
+	require.False(t, f.Match(&Place{Name: "No Tier"}), "places without a tier don't match")
+	require.False(t, f.Match(nil))
+	require.True(t, PlaceFilter{}.Match(&Place{Name: "No Tier"}))
+}
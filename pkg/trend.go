@@ -0,0 +1,133 @@
+package letseat
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/montanaflynn/stats"
+)
+
+// MinTrendVisits is how many rated visits a place needs before we look for a trend
+const MinTrendVisits = 4
+
+// TrendDirection is which way ratings are going
+type TrendDirection string
+
+const (
+	// TrendImproving means ratings are going up
+	TrendImproving TrendDirection = "improving"
+	// TrendDeclining means ratings are going down
+	TrendDeclining TrendDirection = "declining"
+	// TrendSteady means there's no real change, or not enough to tell
+	TrendSteady TrendDirection = "steady"
+)
+
+// RatingPoint is the average rating of a single visit
+type RatingPoint struct {
+	Date   time.Time
+	Rating float64
+}
+
+// Trend is how ratings are moving over time, fit with least squares
+type Trend struct {
+	Visits int
+	// Slope is how much the rating changes per year
+	Slope float64
+	// Significant is true when the slope is unlikely to be chance, at 95% confidence
+	Significant bool
+}
+
+// Direction returns which way the ratings are going. Anything that isn't
+// significant is steady
+func (t Trend) Direction() TrendDirection {
+	switch {
+	case !t.Significant || t.Slope == 0:
+		return TrendSteady
+	case t.Slope > 0:
+		return TrendImproving
+	default:
+		return TrendDeclining
+	}
+}
+
+// String returns a human friendly version of the trend, like "declining, -1.2★ per year over 6 visits"
+func (t Trend) String() string {
+	return fmt.Sprintf("%v, %+.1f★ per year over %v visits", t.Direction(), t.Slope, t.Visits)
+}
+
+// RatingSeries returns the average rating of each rated visit, oldest first
+func (e *Entries) RatingSeries() []RatingPoint {
+	ret := []RatingPoint{}
+	for _, entry := range *e {
+		if entry.Date == nil || len(entry.rated()) == 0 {
+			continue
+		}
+		ret = append(ret, RatingPoint{Date: *entry.Date, Rating: entry.averageRating()})
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Date.Before(ret[j].Date) })
+	return ret
+}
+
+// RatingTrend fits a line through the ratings over time. Returns nil if there
+// aren't enough rated visits to say anything
+func (e *Entries) RatingTrend() *Trend {
+	series := e.RatingSeries()
+	if len(series) < MinTrendVisits {
+		return nil
+	}
+	years := make([]float64, len(series))
+	ratings := make([]float64, len(series))
+	for idx, p := range series {
+		years[idx] = p.Date.Sub(series[0].Date).Hours() / 24 / 365
+		ratings[idx] = p.Rating
+	}
+	ret := &Trend{Visits: len(series)}
+	r, err := stats.Correlation(years, ratings)
+	if err != nil || r == 0 {
+		return ret
+	}
+	sdYears, _ := stats.StandardDeviationPopulation(years)
+	sdRatings, _ := stats.StandardDeviationPopulation(ratings)
+	ret.Slope = r * sdRatings / sdYears
+
+	// t statistic for the slope, compared against a two sided 95% critical value
+	df := len(series) - 2
+	if math.Abs(r) >= 1 {
+		ret.Significant = true
+		return ret
+	}
+	t := r * math.Sqrt(float64(df)/(1-r*r))
+	ret.Significant = math.Abs(t) > tCritical(df)
+	return ret
+}
+
+// tCriticals are the two sided 95% critical values of the t distribution, by degrees of freedom
+var tCriticals = []float64{
+	12.706, 4.303, 3.182, 2.776, 2.571, 2.447, 2.365, 2.306, 2.262, 2.228,
+	2.201, 2.179, 2.160, 2.145, 2.131, 2.120, 2.110, 2.101, 2.093, 2.086,
+	2.080, 2.074, 2.069, 2.064, 2.060, 2.056, 2.052, 2.048, 2.045, 2.042,
+}
+
+func tCritical(df int) float64 {
+	if df > len(tCriticals) {
+		return 1.96
+	}
+	return tCriticals[df-1]
+}
+
+// Trending returns the places whose ratings are clearly going up or down,
+// biggest change first
+func (p PlaceDetails) Trending() PlaceDetails {
+	ret := PlaceDetails{}
+	for _, d := range p {
+		if d.Trend != nil && d.Trend.Direction() != TrendSteady {
+			ret = append(ret, d)
+		}
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		return math.Abs(ret[i].Trend.Slope) > math.Abs(ret[j].Trend.Slope)
+	})
+	return ret
+}
@@ -0,0 +1,50 @@
+package letseat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRatingTrend(t *testing.T) {
+	visits := func(ratings ...int) Entries {
+		ret := Entries{}
+		for idx, r := range ratings {
+			d := mustTime("2023-01-01 19:00").AddDate(0, idx*2, 0)
+			ret = append(ret, Entry{Place: "Pizza Dude", Date: &d, Ratings: map[string]int{"drew": r}})
+		}
+		return ret
+	}
+	tests := map[string]struct {
+		given  Entries
+		expect TrendDirection
+	}{
+		"declining": {given: visits(5, 5, 4, 4, 3, 2), expect: TrendDeclining},
+		"improving": {given: visits(2, 3, 3, 4, 5), expect: TrendImproving},
+		"steady":    {given: visits(4, 4, 4, 4), expect: TrendSteady},
+		"noisy":     {given: visits(5, 2, 4, 3, 5, 2), expect: TrendSteady},
+	}
+	for desc, tt := range tests {
+		got := tt.given.RatingTrend()
+		require.NotNil(t, got, desc)
+		require.Equal(t, tt.expect, got.Direction(), desc)
+	}
+
+	few := visits(5, 1, 5)
+	require.Nil(t, few.RatingTrend(), "not enough visits")
+
+	slipping := visits(5, 5, 4, 4, 3, 2)
+	require.Equal(t, "declining, -3.6★ per year over 6 visits", slipping.RatingTrend().String())
+
+	pd := PlaceDetails{
+		{Name: "Steady", Trend: &Trend{Slope: 0.1}},
+		{Name: "Slipping", Trend: &Trend{Slope: -0.5, Significant: true}},
+		{Name: "New"},
+		{Name: "Crashing", Trend: &Trend{Slope: -2, Significant: true}},
+	}
+	names := []string{}
+	for _, d := range pd.Trending() {
+		names = append(names, d.Name)
+	}
+	require.Equal(t, []string{"Crashing", "Slipping"}, names)
+}
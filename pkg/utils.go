@@ -32,6 +32,9 @@ var unitMap = map[string]int64{
 	"h":  int64(time.Hour),
 	"d":  int64(time.Hour) * 24,
 	"w":  int64(time.Hour) * 168,
+	// Months and years are close enough for filtering. ParseDateRange uses the real calendar
+	"mo": int64(time.Hour) * 24 * 30,
+	"y":  int64(time.Hour) * 24 * 365,
 }
 
 const invalidDuration string = "time: invalid duration "
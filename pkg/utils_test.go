@@ -38,6 +38,14 @@ func TestParseDuration(t *testing.T) {
 			given:  ".5d",
 			expect: time.Hour * 12,
 		},
+		"6 months": {
+			given:  "6mo",
+			expect: time.Hour * 24 * 180,
+		},
+		"a year": {
+			given:  "1y",
+			expect: time.Hour * 24 * 365,
+		},
 	}
 	for desc, tt := range tests {
 		if tt.expectErr == "" {
@@ -0,0 +1,744 @@
+package letseat
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Where is a parsed filter expression, like `takeout and cost < 40`. It is
+// checked against each entry, and can only read from it, never change it
+type Where struct {
+	src  string
+	root whereNode
+}
+
+// WhereError is a problem parsing a where expression, pointing at the column it
+// went wrong at
+type WhereError struct {
+	Expr string
+	// Column is where the problem is, starting at 1
+	Column int
+	Msg    string
+}
+
+// Error satisfies the error interface, showing the expression with a caret
+// under the problem
+func (e *WhereError) Error() string {
+	return fmt.Sprintf("%v at column %v\n  %v\n  %v^", e.Msg, e.Column, e.Expr, strings.Repeat(" ", e.Column-1))
+}
+
+// ParseWhere parses an expression like
+//
+//	rating("andrei") >= 4 and takeout and cost < 40 and weekday in (fri, sat)
+//
+// Fields are place, brand, mode, meal, platform, cost, rating, diners, date,
+// year, month, weekday, hour, price and tier. Modes and meals can be used on
+// their own, like `takeout` or `dinner`. The functions are rating(name),
+// with(name) and guest(name)
+func ParseWhere(s string) (*Where, error) {
+	tokens, err := lexWhere(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &whereParser{src: s, tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.errorf(t.pos, "unexpected %v", t)
+	}
+	if root.typ() != typeBool {
+		return nil, p.errorf(0, "expression must be true or false, not a %v", root.typ())
+	}
+	return &Where{src: s, root: root}, nil
+}
+
+// MustParseWhere parses an expression, or panics on error
+func MustParseWhere(s string) *Where {
+	got, err := ParseWhere(s)
+	if err != nil {
+		panic(err)
+	}
+	return got
+}
+
+// String returns the original expression
+func (w Where) String() string {
+	return w.src
+}
+
+// Match returns true if the entry matches the expression. The places are used
+// to look up things like the price and tier of the place
+func (w Where) Match(e Entry, places Places) bool {
+	v, _ := w.root.eval(&whereContext{entry: e, place: places.Find(e.Place)}).(bool)
+	return v
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokLParen
+	tokRParen
+	tokComma
+	tokOp
+)
+
+type whereToken struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// String describes the token for error messages
+func (t whereToken) String() string {
+	if t.kind == tokEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// is returns true if the token is the given operator or keyword. Keywords are case insensitive
+func (t whereToken) is(s string) bool {
+	switch t.kind {
+	case tokOp:
+		return t.text == s
+	case tokIdent:
+		return strings.EqualFold(t.text, s)
+	}
+	return false
+}
+
+func lexWhere(s string) ([]whereToken, error) {
+	ret := []whereToken{}
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case r == '(':
+			ret = append(ret, whereToken{kind: tokLParen, text: "(", pos: start})
+			i++
+		case r == ')':
+			ret = append(ret, whereToken{kind: tokRParen, text: ")", pos: start})
+			i++
+		case r == ',':
+			ret = append(ret, whereToken{kind: tokComma, text: ",", pos: start})
+			i++
+		case r == '"' || r == '\'':
+			i++
+			for i < len(runes) && runes[i] != r {
+				i++
+			}
+			if i >= len(runes) {
+				return nil, &WhereError{Expr: s, Column: start + 1, Msg: "unterminated string"}
+			}
+			ret = append(ret, whereToken{kind: tokString, text: string(runes[start+1 : i]), pos: start})
+			i++
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			ret = append(ret, whereToken{kind: tokNumber, text: string(runes[start:i]), pos: start})
+		case unicode.IsLetter(r) || r == '_':
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '-') {
+				i++
+			}
+			ret = append(ret, whereToken{kind: tokIdent, text: string(runes[start:i]), pos: start})
+		case strings.ContainsRune("=!<>&|", r):
+			for i < len(runes) && strings.ContainsRune("=!<>&|", runes[i]) {
+				i++
+			}
+			op := string(runes[start:i])
+			if !slices.Contains([]string{"=", "==", "!=", "<", "<=", ">", ">=", "&&", "||", "!"}, op) {
+				return nil, &WhereError{Expr: s, Column: start + 1, Msg: fmt.Sprintf("unknown operator %q", op)}
+			}
+			ret = append(ret, whereToken{kind: tokOp, text: op, pos: start})
+		default:
+			return nil, &WhereError{Expr: s, Column: start + 1, Msg: fmt.Sprintf("unexpected character %q", r)}
+		}
+	}
+	return append(ret, whereToken{kind: tokEOF, pos: len(runes)}), nil
+}
+
+type whereParser struct {
+	src    string
+	tokens []whereToken
+	i      int
+}
+
+func (p *whereParser) peek() whereToken {
+	return p.tokens[p.i]
+}
+
+func (p *whereParser) next() whereToken {
+	t := p.tokens[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *whereParser) errorf(pos int, format string, a ...any) error {
+	return &WhereError{Expr: p.src, Column: pos + 1, Msg: fmt.Sprintf(format, a...)}
+}
+
+func (p *whereParser) expect(kind tokenKind, what string) (whereToken, error) {
+	t := p.next()
+	if t.kind != kind {
+		return t, p.errorf(t.pos, "expected %v but found %v", what, t)
+	}
+	return t, nil
+}
+
+func (p *whereParser) requireBool(n whereNode, pos int, op string) error {
+	if n.typ() != typeBool {
+		return p.errorf(pos, "%v needs true or false on both sides, not a %v", op, n.typ())
+	}
+	return nil
+}
+
+func (p *whereParser) parseOr() (whereNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is("or") || p.peek().is("||") {
+		op := p.next()
+		if err := p.requireBool(left, op.pos, "or"); err != nil {
+			return nil, err
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.requireBool(right, op.pos, "or"); err != nil {
+			return nil, err
+		}
+		left = &logicNode{or: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *whereParser) parseAnd() (whereNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is("and") || p.peek().is("&&") {
+		op := p.next()
+		if err := p.requireBool(left, op.pos, "and"); err != nil {
+			return nil, err
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.requireBool(right, op.pos, "and"); err != nil {
+			return nil, err
+		}
+		left = &logicNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *whereParser) parseNot() (whereNode, error) {
+	if t := p.peek(); t.is("not") || t.is("!") {
+		p.next()
+		n, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		if n.typ() != typeBool {
+			return nil, p.errorf(t.pos, "not needs true or false, not a %v", n.typ())
+		}
+		return &notNode{n: n}, nil
+	}
+	return p.parseCompare()
+}
+
+var compareOps = []string{"=", "==", "!=", "<", "<=", ">", ">="}
+
+func (p *whereParser) parseCompare() (whereNode, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	switch {
+	case t.kind == tokOp && slices.Contains(compareOps, t.text):
+		p.next()
+		right, err := p.parseOperand(left)
+		if err != nil {
+			return nil, err
+		}
+		if left.typ() != right.typ() {
+			return nil, p.errorf(t.pos, "cannot compare a %v to a %v", left.typ(), right.typ())
+		}
+		if left.typ() == typeBool && t.text != "=" && t.text != "==" && t.text != "!=" {
+			return nil, p.errorf(t.pos, "cannot use %v on true or false", t.text)
+		}
+		if right, err = p.normalize(left, right); err != nil {
+			return nil, err
+		}
+		if left, err = p.normalize(right, left); err != nil {
+			return nil, err
+		}
+		return &compareNode{op: t.text, left: left, right: right}, nil
+	case t.is("in"):
+		p.next()
+		return p.parseIn(left, t)
+	}
+	return left, nil
+}
+
+// parseOperand parses the right side of a comparison. Bare words compared to a
+// string are taken as strings, so `weekday = fri` and `mode = takeout` work
+func (p *whereParser) parseOperand(left whereNode) (whereNode, error) {
+	t := p.peek()
+	if left.typ() == typeString && t.kind == tokIdent && p.tokens[p.i+1].kind != tokLParen {
+		if _, isField := whereFields[strings.ToLower(t.text)]; !isField {
+			p.next()
+			return &literalNode{value: t.text, pos: t.pos}, nil
+		}
+	}
+	return p.parsePrimary()
+}
+
+func (p *whereParser) parseIn(left whereNode, in whereToken) (whereNode, error) {
+	if left.typ() == typeBool {
+		return nil, p.errorf(in.pos, "cannot use in on true or false")
+	}
+	if _, err := p.expect(tokLParen, "("); err != nil {
+		return nil, err
+	}
+	items := []whereNode{}
+	for {
+		t := p.next()
+		var item whereNode
+		switch t.kind {
+		case tokString, tokIdent:
+			item = &literalNode{value: t.text, pos: t.pos}
+		case tokNumber:
+			v, err := strconv.ParseFloat(t.text, 64)
+			if err != nil {
+				return nil, p.errorf(t.pos, "invalid number %v", t)
+			}
+			item = &literalNode{value: v, pos: t.pos}
+		default:
+			return nil, p.errorf(t.pos, "expected a value but found %v", t)
+		}
+		if item.typ() != left.typ() {
+			return nil, p.errorf(t.pos, "cannot compare a %v to a %v", left.typ(), item.typ())
+		}
+		item, err := p.normalize(left, item)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+		if t := p.next(); t.kind == tokRParen {
+			break
+		} else if t.kind != tokComma {
+			return nil, p.errorf(t.pos, "expected , or ) but found %v", t)
+		}
+	}
+	return &inNode{left: left, items: items}, nil
+}
+
+// normalize tidies up a literal being compared to a field, like turning
+// "friday" in to "fri" when comparing it to the weekday
+func (p *whereParser) normalize(field, n whereNode) (whereNode, error) {
+	f, ok := field.(*fieldNode)
+	if !ok || f.normalize == nil {
+		return n, nil
+	}
+	lit, ok := n.(*literalNode)
+	if !ok {
+		return n, nil
+	}
+	s, ok := lit.value.(string)
+	if !ok {
+		return n, nil
+	}
+	got, err := f.normalize(s)
+	if err != nil {
+		return nil, p.errorf(lit.pos, "%v", err)
+	}
+	return &literalNode{value: got, pos: lit.pos}, nil
+}
+
+func (p *whereParser) parsePrimary() (whereNode, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		v, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, p.errorf(t.pos, "invalid number %v", t)
+		}
+		return &literalNode{value: v, pos: t.pos}, nil
+	case tokString:
+		return &literalNode{value: t.text, pos: t.pos}, nil
+	case tokLParen:
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokRParen, ")"); err != nil {
+			return nil, err
+		}
+		return n, nil
+	case tokIdent:
+		if p.peek().kind == tokLParen {
+			return p.parseCall(t)
+		}
+		return p.parseIdent(t)
+	}
+	return nil, p.errorf(t.pos, "expected a value but found %v", t)
+}
+
+func (p *whereParser) parseIdent(t whereToken) (whereNode, error) {
+	k := strings.ToLower(t.text)
+	switch k {
+	case "true", "false":
+		return &literalNode{value: k == "true", pos: t.pos}, nil
+	case "and", "or", "not", "in":
+		return nil, p.errorf(t.pos, "expected a value but found %v", t)
+	}
+	if f, ok := whereFields[k]; ok {
+		return &f, nil
+	}
+	if m, err := ParseMode(k); err == nil {
+		return &fieldNode{kind: typeBool, get: func(c *whereContext) any { return c.entry.Mode == m }}, nil
+	}
+	if m, err := ParseMealType(k); err == nil {
+		return &fieldNode{kind: typeBool, get: func(c *whereContext) any { return c.entry.MealType() == m }}, nil
+	}
+	return nil, p.errorf(t.pos, "unknown field %v", t)
+}
+
+func (p *whereParser) parseCall(t whereToken) (whereNode, error) {
+	fn, ok := whereFuncs[strings.ToLower(t.text)]
+	if !ok {
+		return nil, p.errorf(t.pos, "unknown function %v", t)
+	}
+	p.next()
+	arg := p.next()
+	if arg.kind != tokString && arg.kind != tokIdent {
+		return nil, p.errorf(arg.pos, "%v needs a name, like %v(\"drew\")", t.text, t.text)
+	}
+	if _, err := p.expect(tokRParen, ")"); err != nil {
+		return nil, err
+	}
+	name := arg.text
+	return &fieldNode{kind: fn.kind, get: func(c *whereContext) any { return fn.get(c, name) }}, nil
+}
+
+// exprType is the type of value an expression gives back
+type exprType int
+
+const (
+	typeBool exprType = iota
+	typeNumber
+	typeString
+)
+
+// String satisfies the Stringer interface
+func (t exprType) String() string {
+	switch t {
+	case typeNumber:
+		return "number"
+	case typeString:
+		return "string"
+	default:
+		return "boolean"
+	}
+}
+
+type whereContext struct {
+	entry Entry
+	place *Place
+}
+
+// whereNode is a piece of the expression tree. Values are bool, float64,
+// string, or nil when the entry doesn't have that piece of information
+type whereNode interface {
+	eval(c *whereContext) any
+	typ() exprType
+}
+
+type literalNode struct {
+	value any
+	pos   int
+}
+
+func (n *literalNode) eval(_ *whereContext) any {
+	return n.value
+}
+
+func (n *literalNode) typ() exprType {
+	switch n.value.(type) {
+	case float64:
+		return typeNumber
+	case string:
+		return typeString
+	default:
+		return typeBool
+	}
+}
+
+type fieldNode struct {
+	kind      exprType
+	get       func(c *whereContext) any
+	normalize func(string) (string, error)
+}
+
+func (n *fieldNode) eval(c *whereContext) any {
+	return n.get(c)
+}
+
+func (n *fieldNode) typ() exprType {
+	return n.kind
+}
+
+type notNode struct {
+	n whereNode
+}
+
+func (n *notNode) eval(c *whereContext) any {
+	v, _ := n.n.eval(c).(bool)
+	return !v
+}
+
+func (n *notNode) typ() exprType {
+	return typeBool
+}
+
+type logicNode struct {
+	or          bool
+	left, right whereNode
+}
+
+func (n *logicNode) eval(c *whereContext) any {
+	l, _ := n.left.eval(c).(bool)
+	if n.or && l {
+		return true
+	}
+	if !n.or && !l {
+		return false
+	}
+	r, _ := n.right.eval(c).(bool)
+	return r
+}
+
+func (n *logicNode) typ() exprType {
+	return typeBool
+}
+
+type compareNode struct {
+	op          string
+	left, right whereNode
+}
+
+func (n *compareNode) eval(c *whereContext) any {
+	l, r := n.left.eval(c), n.right.eval(c)
+	// Missing information never matches
+	if l == nil || r == nil {
+		return false
+	}
+	cmp := compareValues(l, r)
+	switch n.op {
+	case "=", "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+func (n *compareNode) typ() exprType {
+	return typeBool
+}
+
+type inNode struct {
+	left  whereNode
+	items []whereNode
+}
+
+func (n *inNode) eval(c *whereContext) any {
+	l := n.left.eval(c)
+	if l == nil {
+		return false
+	}
+	for _, item := range n.items {
+		if compareValues(l, item.eval(c)) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *inNode) typ() exprType {
+	return typeBool
+}
+
+// compareValues compares two values of the same type. Strings are compared
+// without caring about case
+func compareValues(a, b any) int {
+	switch av := a.(type) {
+	case float64:
+		bv, _ := b.(float64)
+		switch {
+		case av < bv:
+			return -1
+		case av > bv:
+			return 1
+		}
+		return 0
+	case string:
+		bv, _ := b.(string)
+		return strings.Compare(strings.ToLower(av), strings.ToLower(bv))
+	case bool:
+		if bv, _ := b.(bool); av == bv {
+			return 0
+		}
+		return 1
+	}
+	return 1
+}
+
+// orNil returns nil instead of an empty value, so missing information doesn't match anything
+func orNil[T comparable](v T) any {
+	var zero T
+	if v == zero {
+		return nil
+	}
+	return v
+}
+
+var whereFields = map[string]fieldNode{
+	"place": {kind: typeString, get: func(c *whereContext) any { return c.entry.Place }},
+	"brand": {kind: typeString, get: func(c *whereContext) any {
+		if c.place == nil {
+			return c.entry.Place
+		}
+		return c.place.BrandName()
+	}},
+	"mode": {
+		kind: typeString,
+		get:  func(c *whereContext) any { return orNil(string(c.entry.Mode)) },
+		normalize: func(s string) (string, error) {
+			m, err := ParseMode(s)
+			return string(m), err
+		},
+	},
+	"meal": {
+		kind: typeString,
+		get:  func(c *whereContext) any { return orNil(string(c.entry.MealType())) },
+		normalize: func(s string) (string, error) {
+			m, err := ParseMealType(s)
+			return string(m), err
+		},
+	},
+	"platform": {kind: typeString, get: func(c *whereContext) any { return orNil(c.entry.Platform) }},
+	"cost": {kind: typeNumber, get: func(c *whereContext) any {
+		if c.entry.Cost.IsZero() {
+			return nil
+		}
+		total, err := c.entry.Cost.Total()
+		if err != nil {
+			return nil
+		}
+		return total.Float64()
+	}},
+	"rating": {kind: typeNumber, get: func(c *whereContext) any {
+		if len(c.entry.rated()) == 0 {
+			return nil
+		}
+		return c.entry.averageRating()
+	}},
+	"diners": {kind: typeNumber, get: func(c *whereContext) any { return float64(len(c.entry.Diners())) }},
+	"date": {kind: typeString, get: func(c *whereContext) any {
+		if c.entry.Date == nil {
+			return nil
+		}
+		return c.entry.Date.Format("2006-01-02")
+	}},
+	"year": {kind: typeNumber, get: func(c *whereContext) any {
+		if c.entry.Date == nil {
+			return nil
+		}
+		return float64(c.entry.Date.Year())
+	}},
+	"month": {kind: typeNumber, get: func(c *whereContext) any {
+		if c.entry.Date == nil {
+			return nil
+		}
+		return float64(c.entry.Date.Month())
+	}},
+	"weekday": {
+		kind: typeString,
+		get: func(c *whereContext) any {
+			if c.entry.Date == nil {
+				return nil
+			}
+			return WeekdayKey(c.entry.Date.Weekday())
+		},
+		normalize: func(s string) (string, error) {
+			d, err := ParseWeekday(s)
+			return WeekdayKey(d), err
+		},
+	},
+	"hour": {kind: typeNumber, get: func(c *whereContext) any {
+		if !c.entry.HasTime() {
+			return nil
+		}
+		return float64(c.entry.Date.Hour())
+	}},
+	"price": {kind: typeNumber, get: func(c *whereContext) any {
+		if c.place == nil || c.place.Price == 0 {
+			return nil
+		}
+		return float64(c.place.Price)
+	}},
+	"tier": {kind: typeNumber, get: func(c *whereContext) any {
+		if c.place == nil || c.place.Tier == 0 {
+			return nil
+		}
+		return float64(c.place.Tier)
+	}},
+}
+
+type whereFunc struct {
+	kind exprType
+	get  func(c *whereContext, name string) any
+}
+
+var whereFuncs = map[string]whereFunc{
+	"rating": {kind: typeNumber, get: func(c *whereContext, name string) any {
+		// 0 is "No Rating", so treat it like a missing rating
+		if r := c.entry.Ratings[name]; r != 0 {
+			return float64(r)
+		}
+		return nil
+	}},
+	"with":  {kind: typeBool, get: func(c *whereContext, name string) any { return c.entry.Attended(name) }},
+	"guest": {kind: typeBool, get: func(c *whereContext, name string) any { return slices.Contains(c.entry.Guests, name) }},
+}
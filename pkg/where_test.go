@@ -0,0 +1,106 @@
+package letseat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWhereMatch(t *testing.T) {
+	fri := mustTime("2024-01-05 19:00")
+	entry := Entry{
+		Place:     "Taco Tuesday",
+		Date:      &fri,
+		Mode:      ModeTakeout,
+		Attendees: []string{"drew", "andrei"},
+		Ratings:   map[string]int{"drew": 3, "andrei": 5},
+		Cost:      Bill{Subtotal: MustParseMoney("32.50", "USD")},
+	}
+	places := Places{{Name: "Taco Tuesday", Slug: "taco-tuesday", Price: 1, Tier: 1}}
+	tests := map[string]bool{
+		`rating("andrei") >= 4 and takeout and cost < 40 and weekday in (fri, sat)`: true,
+		`rating(drew) >= 4`:                      false,
+		`rating("nobody") < 10`:                  false,
+		`takeout or dinein`:                      true,
+		`not takeout`:                            false,
+		`!(delivery || dine-in)`:                 true,
+		`mode = takeout`:                         true,
+		`mode != "take out"`:                     false,
+		`meal = dinner and dinner`:               true,
+		`weekday = friday`:                       true,
+		`place = "taco tuesday"`:                 true,
+		`place in ("Pizza Dude", "Sandies")`:     false,
+		`rating = 4`:                             true,
+		`cost >= 32.5 and cost <= 32.50`:         true,
+		`with("andrei") and not guest("andrei")`: true,
+		`diners = 2`:                             true,
+		`date >= "2024-01-01" and year = 2024`:   true,
+		`month in (1, 2) and hour >= 18`:         true,
+		`price <= 2 and tier = 1`:                true,
+		`platform = "doordash"`:                  false,
+		`true and not false`:                     true,
+	}
+	for given, expect := range tests {
+		w, err := ParseWhere(given)
+		require.NoError(t, err, given)
+		require.Equal(t, expect, w.Match(entry, places), given)
+		require.Equal(t, given, w.String())
+	}
+}
+
+func TestWhereMissingInfo(t *testing.T) {
+	entry := Entry{Place: "Mystery Spot"}
+	for _, given := range []string{`cost < 40`, `cost >= 0`, `rating > 0`, `weekday in (fri)`, `price < 4`, `hour < 24`} {
+		require.False(t, MustParseWhere(given).Match(entry, nil), given)
+	}
+	require.True(t, MustParseWhere(`not cost < 40`).Match(entry, nil))
+
+	// Older entries gave everyone a 0 rating, which means no rating at all
+	old := Entry{Place: "Mystery Spot", Ratings: map[string]int{"drew": 4, "james": 0}}
+	require.False(t, MustParseWhere(`rating("james") < 3`).Match(old, nil))
+	require.True(t, MustParseWhere(`rating("drew") < 5`).Match(old, nil))
+	require.True(t, MustParseWhere(`rating >= 4`).Match(old, nil), "the average only counts real ratings")
+	require.False(t, MustParseWhere(`rating >= 0`).Match(Entry{Ratings: map[string]int{"james": 0}}, nil))
+}
+
+func TestParseWhereErrors(t *testing.T) {
+	tests := map[string]struct {
+		column int
+		msg    string
+	}{
+		`takeout and`:             {column: 12, msg: "expected a value but found end of expression"},
+		`cost < "cheap"`:          {column: 6, msg: "cannot compare a number to a string"},
+		`takeout and flavor > 3`:  {column: 13, msg: `unknown field "flavor"`},
+		`weekday in (fri, funday`: {column: 18, msg: "unknown weekday: funday"},
+		`weekday in (fri sat)`:    {column: 17, msg: `expected , or ) but found "sat"`},
+		`cost`:                    {column: 1, msg: "expression must be true or false, not a number"},
+		`cost and takeout`:        {column: 6, msg: "and needs true or false on both sides, not a number"},
+		`place = 'unterminated`:   {column: 9, msg: "unterminated string"},
+		`cost =< 3`:               {column: 6, msg: `unknown operator "=<"`},
+		`cost < 3 $`:              {column: 10, msg: "unexpected character '$'"},
+		`(takeout`:                {column: 9, msg: "expected ) but found end of expression"},
+		`smell("drew") > 3`:       {column: 1, msg: `unknown function "smell"`},
+		`takeout delivery`:        {column: 9, msg: `unexpected "delivery"`},
+		`mode = teleport`:         {column: 8, msg: "unknown mode: teleport"},
+	}
+	for given, tt := range tests {
+		_, err := ParseWhere(given)
+		require.Error(t, err, given)
+		var werr *WhereError
+		require.ErrorAs(t, err, &werr, given)
+		require.Equal(t, tt.msg, werr.Msg, given)
+		require.Equal(t, tt.column, werr.Column, given)
+	}
+
+	_, err := ParseWhere(`cost < "cheap"`)
+	require.EqualError(t, err, "cannot compare a number to a string at column 6\n  cost < \"cheap\"\n       ^")
+}
+
+func TestEntryFilterWhere(t *testing.T) {
+	entries := Entries{
+		{Place: "Taco Tuesday", Mode: ModeTakeout},
+		{Place: "Pizza Dude", Mode: ModeDineIn},
+	}
+	got := entries.filter(&EntryFilter{Where: MustParseWhere("takeout")})
+	require.Equal(t, []string{"Taco Tuesday"}, got.UniquePlaceNames())
+}
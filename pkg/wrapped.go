@@ -0,0 +1,237 @@
+package letseat
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// WrappedTopN is how many places are listed in each part of a Wrapped
+const WrappedTopN = 3
+
+// Wrapped is a look back over a year of eating out
+type Wrapped struct {
+	Year  int   `yaml:"year"`
+	Meals int   `yaml:"meals"`
+	Spent Money `yaml:"spent"`
+	// NewPlaces are the places visited for the very first time this year, in order
+	NewPlaces   []string      `yaml:"new_places"`
+	MostVisited []PlaceCount  `yaml:"most_visited"`
+	BestRated   []PlaceRating `yaml:"best_rated"`
+	// Favorites are each person's favorite place this year
+	Favorites     map[string]string `yaml:"favorites"`
+	LongestStreak *Streak           `yaml:"longest_streak,omitempty"`
+	// PlaceStreak is the most visits in a row to the same place
+	PlaceStreak  *PlaceCount    `yaml:"place_streak,omitempty"`
+	BusiestMonth *MonthCount    `yaml:"busiest_month,omitempty"`
+	Surprise     *RatedSurprise `yaml:"surprise,omitempty"`
+}
+
+// PlaceCount is how many times a place was visited
+type PlaceCount struct {
+	Place  string `yaml:"place"`
+	Visits int    `yaml:"visits"`
+}
+
+// PlaceRating is how well a place was rated
+type PlaceRating struct {
+	Place string `yaml:"place"`
+	// Rating is the Bayesian rating the place was ranked on
+	Rating  float64 `yaml:"rating"`
+	Average float64 `yaml:"average"`
+	Visits  int     `yaml:"visits"`
+}
+
+// Streak is a run of days in a row with a meal out
+type Streak struct {
+	Start time.Time `yaml:"start"`
+	End   time.Time `yaml:"end"`
+	Days  int       `yaml:"days"`
+}
+
+// MonthCount is how many meals were had out in a month
+type MonthCount struct {
+	Month time.Month `yaml:"month"`
+	Meals int        `yaml:"meals"`
+}
+
+// RatedSurprise is the meal that was rated furthest from what the place usually gets
+type RatedSurprise struct {
+	Place    string    `yaml:"place"`
+	Date     time.Time `yaml:"date"`
+	Rating   float64   `yaml:"rating"`
+	Expected float64   `yaml:"expected"`
+}
+
+// Wrapped looks back over a year of the entries. The entries should cover
+// every year, so first visits and usual ratings can be worked out
+func (e *Entries) Wrapped(year int) Wrapped {
+	ye := Entries{}
+	for _, entry := range *e {
+		if entry.Date != nil && entry.Date.Year() == year {
+			ye = append(ye, entry)
+		}
+	}
+	sort.SliceStable(ye, func(i, j int) bool { return ye[i].Date.Before(*ye[j].Date) })
+	return Wrapped{
+		Year:          year,
+		Meals:         len(ye),
+		Spent:         ye.CostReport().Total,
+		NewPlaces:     e.newPlaces(year),
+		MostVisited:   ye.mostVisited(),
+		BestRated:     ye.bestRated(),
+		Favorites:     ye.favorites(),
+		LongestStreak: ye.longestStreak(),
+		PlaceStreak:   ye.placeStreak(),
+		BusiestMonth:  ye.busiestMonth(),
+		Surprise:      e.surprise(ye),
+	}
+}
+
+// newPlaces returns the places first visited in the given year
+func (e *Entries) newPlaces(year int) []string {
+	first := e.FirstVisits()
+	ret := []string{}
+	for place, f := range first {
+		if f.Year() == year {
+			ret = append(ret, place)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool { return first[ret[i]].Before(first[ret[j]]) })
+	return ret
+}
+
+func (e *Entries) mostVisited() []PlaceCount {
+	counts := map[string]int{}
+	for _, entry := range *e {
+		counts[entry.Place]++
+	}
+	ret := make([]PlaceCount, 0, len(counts))
+	for place, n := range counts {
+		ret = append(ret, PlaceCount{Place: place, Visits: n})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Visits != ret[j].Visits {
+			return ret[i].Visits > ret[j].Visits
+		}
+		return ret[i].Place < ret[j].Place
+	})
+	return ret[:min(len(ret), WrappedTopN)]
+}
+
+// bestRated ranks by the Bayesian rating, so one great meal doesn't win the year
+func (e *Entries) bestRated() []PlaceRating {
+	places := e.UniquePlaceNames()
+	pd := make(PlaceDetails, len(places))
+	for idx, place := range places {
+		pd[idx] = *e.placeDetails(place)
+	}
+	pd.Score()
+	pd.SortBy(RankByBayesian)
+	ret := []PlaceRating{}
+	for _, d := range pd {
+		if d.Rated() == 0 || len(ret) == WrappedTopN {
+			continue
+		}
+		ret = append(ret, PlaceRating{Place: d.Name, Rating: round2(d.BayesianRating), Average: round2(d.AverageRating), Visits: d.Visits})
+	}
+	return ret
+}
+
+func (e *Entries) favorites() map[string]string {
+	ret := map[string]string{}
+	for _, p := range e.PeopleEnhanced() {
+		best := ""
+		for place, r := range p.PlaceAvgRatings {
+			if best == "" || r > p.PlaceAvgRatings[best] || (r == p.PlaceAvgRatings[best] && place < best) {
+				best = place
+			}
+		}
+		if best != "" {
+			ret[p.Name] = best
+		}
+	}
+	return ret
+}
+
+// longestStreak finds the most days in a row with a meal out. Expects the entries to be sorted
+func (e *Entries) longestStreak() *Streak {
+	var best, current *Streak
+	for _, entry := range *e {
+		d := time.Date(entry.Date.Year(), entry.Date.Month(), entry.Date.Day(), 0, 0, 0, 0, entry.Date.Location())
+		switch {
+		case current != nil && d.Equal(current.End):
+			continue
+		case current != nil && d.Equal(current.End.AddDate(0, 0, 1)):
+			current.End = d
+			current.Days++
+		default:
+			current = &Streak{Start: d, End: d, Days: 1}
+		}
+		if best == nil || current.Days > best.Days {
+			s := *current
+			best = &s
+		}
+	}
+	return best
+}
+
+// placeStreak finds the most visits in a row to the same place. Expects the entries to be sorted
+func (e *Entries) placeStreak() *PlaceCount {
+	var best *PlaceCount
+	current := PlaceCount{}
+	for _, entry := range *e {
+		if entry.Place == current.Place {
+			current.Visits++
+		} else {
+			current = PlaceCount{Place: entry.Place, Visits: 1}
+		}
+		if best == nil || current.Visits > best.Visits {
+			s := current
+			best = &s
+		}
+	}
+	return best
+}
+
+func (e *Entries) busiestMonth() *MonthCount {
+	counts := map[time.Month]int{}
+	for _, entry := range *e {
+		counts[entry.Date.Month()]++
+	}
+	var ret *MonthCount
+	for m := time.January; m <= time.December; m++ {
+		if counts[m] > 0 && (ret == nil || counts[m] > ret.Meals) {
+			ret = &MonthCount{Month: m, Meals: counts[m]}
+		}
+	}
+	return ret
+}
+
+// surprise finds the meal in year that was rated the furthest from how the
+// place is rated the rest of the time, good or bad
+func (e *Entries) surprise(year Entries) *RatedSurprise {
+	var ret *RatedSurprise
+	for _, entry := range year {
+		if len(entry.rated()) == 0 {
+			continue
+		}
+		var total float64
+		n := 0
+		for _, other := range *e {
+			if other.Place != entry.Place || len(other.rated()) == 0 || (other.Date != nil && other.Date.Equal(*entry.Date)) {
+				continue
+			}
+			total += other.averageRating()
+			n++
+		}
+		if n == 0 {
+			continue
+		}
+		s := RatedSurprise{Place: entry.Place, Date: *entry.Date, Rating: round2(entry.averageRating()), Expected: round2(total / float64(n))}
+		if ret == nil || math.Abs(s.Rating-s.Expected) > math.Abs(ret.Rating-ret.Expected) {
+			ret = &s
+		}
+	}
+	return ret
+}
@@ -0,0 +1,67 @@
+package letseat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrapped(t *testing.T) {
+	meal := func(place, date string, ratings map[string]int) Entry {
+		return Entry{Place: place, Date: toPTR(mustTime(date)), Ratings: ratings}
+	}
+	entries := Entries{
+		meal("Pizza Dude", "2022-06-01 19:00", map[string]int{"drew": 5}),
+		meal("Pizza Dude", "2022-07-01 19:00", map[string]int{"drew": 5}),
+		meal("Pizza Dude", "2023-03-01 19:00", map[string]int{"drew": 2, "james": 2}),
+		meal("Pizza Dude", "2023-03-02 19:00", map[string]int{"drew": 5}),
+		meal("Taco Tuesday", "2023-03-03 12:00", map[string]int{"drew": 4, "james": 5}),
+		meal("Taco Tuesday", "2023-03-03 19:00", map[string]int{"drew": 4}),
+		meal("BBQ Papa", "2023-07-04 19:00", map[string]int{"james": 3}),
+		meal("Taco Tuesday", "2024-01-01 19:00", map[string]int{"drew": 1}),
+	}
+	entries[2].Cost = Bill{Subtotal: MustParseMoney("30", "USD")}
+
+	got := entries.Wrapped(2023)
+	require.Equal(t, 2023, got.Year)
+	require.Equal(t, 5, got.Meals)
+	require.Equal(t, "$30.00", got.Spent.String())
+	require.Equal(t, []string{"Taco Tuesday", "BBQ Papa"}, got.NewPlaces)
+	require.Equal(t, []PlaceCount{{Place: "Pizza Dude", Visits: 2}, {Place: "Taco Tuesday", Visits: 2}, {Place: "BBQ Papa", Visits: 1}}, got.MostVisited)
+	require.Equal(t, "Taco Tuesday", got.BestRated[0].Place)
+	require.Equal(t, map[string]string{"drew": "Taco Tuesday", "james": "Taco Tuesday"}, got.Favorites)
+	require.Equal(t, &Streak{
+		Start: mustTime("2023-03-01 00:00"),
+		End:   mustTime("2023-03-03 00:00"),
+		Days:  3,
+	}, got.LongestStreak)
+	require.Equal(t, &PlaceCount{Place: "Pizza Dude", Visits: 2}, got.PlaceStreak)
+	require.Equal(t, &MonthCount{Month: time.March, Meals: 4}, got.BusiestMonth)
+	require.Equal(t, &RatedSurprise{
+		Place:    "Pizza Dude",
+		Date:     mustTime("2023-03-01 19:00"),
+		Rating:   2,
+		Expected: 5,
+	}, got.Surprise)
+
+	empty := entries.Wrapped(2010)
+	require.Equal(t, 0, empty.Meals)
+	require.Nil(t, empty.LongestStreak)
+	require.Nil(t, empty.Surprise)
+}
+
+func TestWrappedSurpriseSkipsNoRating(t *testing.T) {
+	entries := Entries{
+		{Place: "Pizza Dude", Date: toPTR(mustTime("2022-06-01 19:00")), Ratings: map[string]int{"drew": 4}},
+		{Place: "Pizza Dude", Date: toPTR(mustTime("2023-03-01 19:00")), Ratings: map[string]int{"drew": 4, "james": 0}},
+		// Nobody rated this one, so it can't be a 0★ surprise
+		{Place: "Pizza Dude", Date: toPTR(mustTime("2023-03-02 19:00")), Ratings: map[string]int{"drew": 0, "james": 0}},
+	}
+	got := entries.Wrapped(2023).Surprise
+	require.NotNil(t, got)
+	require.Equal(t, mustTime("2023-03-01 19:00"), got.Date)
+	require.Equal(t, 4.0, got.Rating)
+	require.Equal(t, 4.0, got.Expected)
+	require.Len(t, entries.RatingSeries(), 2)
+}